@@ -23,6 +23,72 @@ type WorkspaceInfoFetcher func(apiKey string) (*WorkspaceInfo, error)
 func PromptForNewWorkspace(cfg *config.Config, currentDir string, fetchInfo WorkspaceInfoFetcher) (*config.Workspace, error) {
 	reader := bufio.NewReader(os.Stdin)
 
+	key, info, err := promptForAPIKey(reader, fetchInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := cfg.GetWorkspaceByID(info.ID)
+	if existing != nil {
+		existing.APIKey = key
+		if err := cfg.AddWorkspace(*existing); err != nil {
+			return nil, fmt.Errorf("failed to update workspace: %w", err)
+		}
+		if err := cfg.SetDirectoryWorkspace(currentDir, existing.ID); err != nil {
+			return nil, fmt.Errorf("failed to save directory mapping: %w", err)
+		}
+		fmt.Printf("Updated API key for workspace '%s'.\n\n", existing.Name)
+		return existing, nil
+	}
+
+	ws := config.Workspace{
+		ID:     info.ID,
+		Name:   info.Name,
+		APIKey: key,
+	}
+
+	if err := cfg.AddWorkspace(ws); err != nil {
+		return nil, fmt.Errorf("failed to save workspace: %w", err)
+	}
+
+	if err := cfg.SetDirectoryWorkspace(currentDir, ws.ID); err != nil {
+		return nil, fmt.Errorf("failed to save directory mapping: %w", err)
+	}
+
+	fmt.Printf("Added workspace '%s' for this directory.\n\n", ws.Name)
+	return &ws, nil
+}
+
+// PromptForRenewedAPIKey asks for a replacement API key after Linear rejected
+// the stored one (e.g. expired or revoked), and saves it to the workspace
+func PromptForRenewedAPIKey(cfg *config.Config, ws *config.Workspace, fetchInfo WorkspaceInfoFetcher) (*config.Workspace, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Printf("Linear rejected the API key for workspace '%s'.\n", ws.Name)
+	fmt.Println("It may have expired or been revoked. Create a new key to continue.")
+
+	key, info, err := promptForAPIKey(reader, fetchInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.ID != ws.ID {
+		return nil, fmt.Errorf("API key belongs to workspace '%s', not '%s'", info.Name, ws.Name)
+	}
+
+	updated := *ws
+	updated.APIKey = key
+	if err := cfg.AddWorkspace(updated); err != nil {
+		return nil, fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	fmt.Printf("Updated API key for workspace '%s'.\n\n", updated.Name)
+	return cfg.GetWorkspaceByID(updated.ID), nil
+}
+
+// promptForAPIKey walks the user through creating an API key, reads it and
+// validates it against Linear
+func promptForAPIKey(reader *bufio.Reader, fetchInfo WorkspaceInfoFetcher) (string, *WorkspaceInfo, error) {
 	fmt.Println()
 	fmt.Println("To create an API key:")
 	fmt.Println("  1. Under 'API keys', click 'Create key'")
@@ -42,12 +108,12 @@ func PromptForNewWorkspace(cfg *config.Config, currentDir string, fetchInfo Work
 
 	key, err := reader.ReadString('\n')
 	if err != nil {
-		return nil, fmt.Errorf("failed to read input: %w", err)
+		return "", nil, fmt.Errorf("failed to read input: %w", err)
 	}
 
 	key = strings.TrimSpace(key)
 	if key == "" {
-		return nil, fmt.Errorf("no API key provided")
+		return "", nil, fmt.Errorf("no API key provided")
 	}
 
 	if !strings.HasPrefix(key, "lin_api_") {
@@ -58,7 +124,7 @@ func PromptForNewWorkspace(cfg *config.Config, currentDir string, fetchInfo Work
 		confirm, _ := reader.ReadString('\n')
 		confirm = strings.TrimSpace(strings.ToLower(confirm))
 		if confirm != "y" && confirm != "yes" {
-			return nil, fmt.Errorf("authentication cancelled")
+			return "", nil, fmt.Errorf("authentication cancelled")
 		}
 	}
 
@@ -67,38 +133,10 @@ func PromptForNewWorkspace(cfg *config.Config, currentDir string, fetchInfo Work
 
 	info, err := fetchInfo(key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to validate API key: %w", err)
+		return "", nil, fmt.Errorf("failed to validate API key: %w", err)
 	}
 
-	existing := cfg.GetWorkspaceByID(info.ID)
-	if existing != nil {
-		existing.APIKey = key
-		if err := cfg.AddWorkspace(*existing); err != nil {
-			return nil, fmt.Errorf("failed to update workspace: %w", err)
-		}
-		if err := cfg.SetDirectoryWorkspace(currentDir, existing.ID); err != nil {
-			return nil, fmt.Errorf("failed to save directory mapping: %w", err)
-		}
-		fmt.Printf("Updated API key for workspace '%s'.\n\n", existing.Name)
-		return existing, nil
-	}
-
-	ws := config.Workspace{
-		ID:     info.ID,
-		Name:   info.Name,
-		APIKey: key,
-	}
-
-	if err := cfg.AddWorkspace(ws); err != nil {
-		return nil, fmt.Errorf("failed to save workspace: %w", err)
-	}
-
-	if err := cfg.SetDirectoryWorkspace(currentDir, ws.ID); err != nil {
-		return nil, fmt.Errorf("failed to save directory mapping: %w", err)
-	}
-
-	fmt.Printf("Added workspace '%s' for this directory.\n\n", ws.Name)
-	return &ws, nil
+	return key, info, nil
 }
 
 func UseExistingWorkspace(cfg *config.Config, ws *config.Workspace, currentDir string) error {
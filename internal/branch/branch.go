@@ -0,0 +1,187 @@
+// Package branch names the git branches issues are worked on, after a
+// configurable template or as Linear suggests.
+package branch
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"linc/internal/config"
+	"linc/internal/linear"
+)
+
+// Data is what a branch name template can use
+type Data struct {
+	Identifier string // issue identifier, e.g. ENG-123
+	Team       string // team key, e.g. ENG
+	Slug       string // title, lowercased and hyphenated
+	Type       string // derived from the issue's labels, e.g. fix
+	Assignee   string // assignee's handle, empty if unassigned
+	Linear     string // Linear's suggested branch name
+}
+
+// DefaultType is the .Type of issues without a label that maps to one
+const DefaultType = "feat"
+
+// defaultTypes maps common label names, lowercased, to branch types
+var defaultTypes = map[string]string{
+	"bug":           "fix",
+	"feature":       "feat",
+	"improvement":   "feat",
+	"enhancement":   "feat",
+	"chore":         "chore",
+	"documentation": "docs",
+	"docs":          "docs",
+	"refactor":      "refactor",
+	"performance":   "perf",
+	"test":          "test",
+	"tests":         "test",
+}
+
+// maxSlug is the longest .Slug, cut at a word boundary
+const maxSlug = 40
+
+var templateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// Namer names issue branches. A nil Namer names them as Linear suggests.
+type Namer struct {
+	tmpl        *template.Template
+	types       map[string]string
+	defaultType string
+}
+
+// New returns a Namer for the branch name template of a workspace and the
+// repository at repoRoot, or nil if none is configured
+func New(cfg *config.Config, workspaceID, repoRoot string) (*Namer, error) {
+	text := cfg.BranchTemplateFor(workspaceID, repoRoot)
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("branch").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	// Catch fields Data doesn't have now rather than on every name
+	if err := tmpl.Execute(io.Discard, Data{}); err != nil {
+		return nil, err
+	}
+
+	n := &Namer{tmpl: tmpl, types: make(map[string]string), defaultType: DefaultType}
+	for label, t := range defaultTypes {
+		n.types[label] = t
+	}
+	if names := cfg.BranchNames; names != nil {
+		for label, t := range names.Types {
+			n.types[strings.ToLower(label)] = t
+		}
+		if names.DefaultType != "" {
+			n.defaultType = names.DefaultType
+		}
+	}
+	return n, nil
+}
+
+// Templated reports whether branches are named after a template rather than
+// as Linear suggests
+func (n *Namer) Templated() bool {
+	return n != nil
+}
+
+// Name returns the branch to work on issue in. Without a template, or if
+// the template yields nothing, that is Linear's suggested branch name.
+func (n *Namer) Name(issue linear.Issue) string {
+	if n == nil {
+		return issue.BranchName
+	}
+	var sb strings.Builder
+	if err := n.tmpl.Execute(&sb, n.data(issue)); err != nil {
+		return issue.BranchName
+	}
+	if name := clean(sb.String()); name != "" {
+		return name
+	}
+	return issue.BranchName
+}
+
+// Matches reports whether branch is issue's, named after the template or as
+// Linear suggests
+func (n *Namer) Matches(branch string, issue linear.Issue) bool {
+	if branch == "" {
+		return false
+	}
+	if issue.BranchName != "" && branch == issue.BranchName {
+		return true
+	}
+	return n != nil && branch == n.Name(issue)
+}
+
+// Prefer returns the branch to work on an issue in, given the one named after
+// the template and Linear's suggested one: Linear's if only it exists yet,
+// so work started on it elsewhere continues there, else the templated one
+func Prefer(templated, linearName string, exists func(branch string) bool) string {
+	if linearName == "" || linearName == templated || exists(templated) || !exists(linearName) {
+		return templated
+	}
+	return linearName
+}
+
+func (n *Namer) data(issue linear.Issue) Data {
+	d := Data{
+		Identifier: issue.Identifier,
+		Team:       issue.Team.Key,
+		Slug:       slugify(issue.Title, maxSlug),
+		Type:       n.defaultType,
+		Linear:     issue.BranchName,
+	}
+	for _, label := range issue.Labels {
+		if t, ok := n.types[strings.ToLower(label.Name)]; ok {
+			d.Type = t
+			break
+		}
+	}
+	if a := issue.Assignee; a != nil {
+		handle := a.DisplayName
+		if handle == "" {
+			handle, _, _ = strings.Cut(a.Email, "@")
+		}
+		d.Assignee = slugify(handle, 0)
+	}
+	return d
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify lowercases s and joins its words with hyphens, cutting it at a
+// word boundary to at most max characters if max is positive
+func slugify(s string, max int) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if max > 0 && len(slug) > max {
+		slug = slug[:max]
+		if i := strings.LastIndex(slug, "-"); i > 0 {
+			slug = slug[:i]
+		}
+		slug = strings.Trim(slug, "-")
+	}
+	return slug
+}
+
+// invalidRef matches what git doesn't allow in branch names
+var invalidRef = regexp.MustCompile(`[\x00-\x20\x7f~^:?*\[\\]+|\.\.+|@\{`)
+
+// clean makes name a valid git branch name, or empty if nothing is left
+func clean(name string) string {
+	name = invalidRef.ReplaceAllString(name, "-")
+	var parts []string
+	for _, part := range strings.Split(name, "/") {
+		part = strings.TrimSuffix(strings.Trim(part, ".-"), ".lock")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,183 @@
+package branch
+
+import (
+	"testing"
+
+	"linc/internal/config"
+	"linc/internal/linear"
+)
+
+// newNamer returns a Namer for text as the default template
+func newNamer(t *testing.T, text string) *Namer {
+	t.Helper()
+	n, err := New(&config.Config{BranchNames: &config.BranchNamesConfig{Template: text}}, "ws-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func testIssue(title string, labels ...string) linear.Issue {
+	issue := linear.Issue{
+		Identifier: "ENG-1",
+		Title:      title,
+		BranchName: "eng-1-linear-name",
+		Team:       linear.Team{Key: "ENG"},
+		Assignee:   &linear.User{DisplayName: "Ada Lovelace", Email: "ada@example.com"},
+	}
+	for _, label := range labels {
+		issue.Labels = append(issue.Labels, linear.Label{Name: label})
+	}
+	return issue
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"Fix login redirect loop", 0, "fix-login-redirect-loop"},
+		{"  Handle 404s -- properly!! ", 0, "handle-404s-properly"},
+		{"Über café", 0, "ber-caf"},
+		{"日本語のタイトル", 0, ""},
+		{"!!!", 0, ""},
+		// Cut at the last word boundary within max
+		{"A very long title that goes well beyond forty characters", 40, "a-very-long-title-that-goes-well-beyond"},
+		// A single word longer than max is cut mid-word
+		{"Supercalifragilistic words", 10, "supercalif"},
+		{"Short", 40, "short"},
+	}
+	for _, tt := range tests {
+		if got := slugify(tt.s, tt.max); got != tt.want {
+			t.Errorf("slugify(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"feat/eng-1-fix-login", "feat/eng-1-fix-login"},
+		{"my branch: ~new^", "my-branch-new"},
+		{"what?*[x]\\y", "what-x]-y"},
+		{"a..b", "a-b"},
+		{"a...b", "a-b"},
+		{"fix@{upstream}", "fix-upstream}"},
+		{"user@example", "user@example"},
+		{"eng-1.lock", "eng-1"},
+		{"refs.lock/eng-1", "refs/eng-1"},
+		{"feat//eng-1/", "feat/eng-1"},
+		{".hidden/-dash-/trailing.", "hidden/dash/trailing"},
+		{"...", ""},
+		{"/", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := clean(tt.name); got != tt.want {
+			t.Errorf("clean(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		template string
+		issue    linear.Issue
+		want     string
+	}{
+		{"{{.Type}}/{{.Identifier}}-{{.Slug}}", testIssue("Fix login redirect loop", "Bug"), "fix/ENG-1-fix-login-redirect-loop"},
+		{"{{.Type}}/{{.Identifier}}-{{.Slug}}", testIssue("Add dark mode"), "feat/ENG-1-add-dark-mode"},
+		{"{{.Assignee}}/{{lower .Identifier}}", testIssue("Anything"), "ada-lovelace/eng-1"},
+		{"{{.Team}}/{{.Linear}}", testIssue("Anything"), "ENG/eng-1-linear-name"},
+		// Characters git refuses are replaced
+		{"{{.Identifier}}:{{.Slug}}..", testIssue("Tidy up"), "ENG-1-tidy-up"},
+		// A title that slugifies to nothing leaves nothing to name the
+		// branch after, so Linear's name is used
+		{"{{.Slug}}", testIssue("日本語のタイトル"), "eng-1-linear-name"},
+	}
+	for _, tt := range tests {
+		if got := newNamer(t, tt.template).Name(tt.issue); got != tt.want {
+			t.Errorf("%s for %q = %q, want %q", tt.template, tt.issue.Title, got, tt.want)
+		}
+	}
+
+	// Without a template, branches are named as Linear suggests
+	var none *Namer
+	if got := none.Name(testIssue("Anything")); got != "eng-1-linear-name" {
+		t.Errorf("nil Namer named the branch %q", got)
+	}
+}
+
+func TestNewRejectsBadTemplates(t *testing.T) {
+	for _, text := range []string{"{{.Identifier", "{{.Nope}}", "{{shout .Slug}}"} {
+		cfg := &config.Config{BranchNames: &config.BranchNamesConfig{Template: text}}
+		if _, err := New(cfg, "ws-test", ""); err == nil {
+			t.Errorf("New(%q) succeeded", text)
+		}
+	}
+	if n, err := New(&config.Config{}, "ws-test", ""); n != nil || err != nil {
+		t.Errorf("New without a template = %v, %v, want nil", n, err)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	issue := testIssue("Fix login redirect loop", "Bug")
+	n := newNamer(t, "{{.Type}}/{{.Identifier}}-{{.Slug}}")
+
+	tests := []struct {
+		namer  *Namer
+		branch string
+		want   bool
+	}{
+		{n, "fix/ENG-1-fix-login-redirect-loop", true},
+		{n, "eng-1-linear-name", true},
+		{n, "fix/ENG-2-something-else", false},
+		{n, "", false},
+		{nil, "eng-1-linear-name", true},
+		{nil, "fix/ENG-1-fix-login-redirect-loop", false},
+	}
+	for _, tt := range tests {
+		if got := tt.namer.Matches(tt.branch, issue); got != tt.want {
+			t.Errorf("Matches(%q) with template %v = %v, want %v", tt.branch, tt.namer.Templated(), got, tt.want)
+		}
+	}
+
+	// An issue without a suggested branch doesn't match the empty branch
+	if (*Namer)(nil).Matches("", linear.Issue{}) {
+		t.Error("the empty branch matched an issue without a branch name")
+	}
+}
+
+func TestPrefer(t *testing.T) {
+	const templated, linearName = "feat/eng-1-fix", "eng-1-fix"
+
+	tests := []struct {
+		name       string
+		linearName string
+		existing   []string
+		want       string
+	}{
+		{"neither exists", linearName, nil, templated},
+		{"only Linear's exists", linearName, []string{linearName}, linearName},
+		{"only the templated exists", linearName, []string{templated}, templated},
+		{"both exist", linearName, []string{templated, linearName}, templated},
+		{"no Linear name", "", []string{""}, templated},
+		{"same name", templated, []string{templated}, templated},
+	}
+	for _, tt := range tests {
+		exists := func(branch string) bool {
+			for _, b := range tt.existing {
+				if b == branch {
+					return true
+				}
+			}
+			return false
+		}
+		if got := Prefer(templated, tt.linearName, exists); got != tt.want {
+			t.Errorf("%s: Prefer() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
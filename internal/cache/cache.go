@@ -0,0 +1,127 @@
+// Package cache stores the last known Linear data for a workspace on disk so
+// linc can render immediately at startup while revalidating in the background.
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"linc/internal/config"
+	"linc/internal/linear"
+)
+
+// Store reads and writes cached data under ~/.linc/cache/<workspace ID>
+type Store struct {
+	dir string
+}
+
+// TeamIssues is the cached issue lists of a team
+type TeamIssues struct {
+	// SyncedAt is when the lists were last fetched from Linear (RFC 3339),
+	// used to fetch only issues updated since
+	SyncedAt    string          `json:"syncedAt"`
+	MyIssues    []linear.Issue  `json:"myIssues"`
+	MyPageInfo  linear.PageInfo `json:"myPageInfo"`
+	AllIssues   []linear.Issue  `json:"allIssues"`
+	AllPageInfo linear.PageInfo `json:"allPageInfo"`
+	// SnapshotAt is when the lists were last fetched in full rather than
+	// patched with updates
+	SnapshotAt string `json:"snapshotAt,omitempty"`
+}
+
+// Open returns the cache store for a workspace. Nothing is created on disk
+// until data is saved.
+func Open(workspaceID string) (*Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: filepath.Join(dir, "cache", workspaceID)}, nil
+}
+
+// Dir returns the directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Viewer returns the cached viewer, or nil if none is cached
+func (s *Store) Viewer() (*linear.ViewerResponse, error) {
+	var viewer linear.ViewerResponse
+	if ok, err := s.read("viewer.json", &viewer); !ok {
+		return nil, err
+	}
+	return &viewer, nil
+}
+
+func (s *Store) SaveViewer(viewer *linear.ViewerResponse) error {
+	return s.write("viewer.json", viewer)
+}
+
+// TeamStates returns the cached workflow states of a team, or nil if none are cached
+func (s *Store) TeamStates(teamID string) ([]linear.State, error) {
+	var states []linear.State
+	if ok, err := s.read("states-"+teamID+".json", &states); !ok {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (s *Store) SaveTeamStates(teamID string, states []linear.State) error {
+	return s.write("states-"+teamID+".json", states)
+}
+
+// TeamIssues returns the cached issue lists of a team, or nil if none are cached
+func (s *Store) TeamIssues(teamID string) (*TeamIssues, error) {
+	var issues TeamIssues
+	if ok, err := s.read("issues-"+teamID+".json", &issues); !ok {
+		return nil, err
+	}
+	return &issues, nil
+}
+
+func (s *Store) SaveTeamIssues(teamID string, issues *TeamIssues) error {
+	return s.write("issues-"+teamID+".json", issues)
+}
+
+// read decodes a cache file, reporting false if it is missing or unreadable
+func (s *Store) read(name string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// write encodes a cache file, replacing it atomically
+func (s *Store) write(name string, v interface{}) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type Workspace struct {
@@ -15,11 +16,88 @@ type Workspace struct {
 }
 
 type Config struct {
-	Workspaces  []Workspace       `json:"workspaces,omitempty"`
-	Directories map[string]string `json:"directories,omitempty"` // path -> workspace ID
-	Provider    string            `json:"provider,omitempty"`    // agent provider: claude, echo, etc.
+	Workspaces   []Workspace       `json:"workspaces,omitempty"`
+	Directories  map[string]string `json:"directories,omitempty"`  // path -> workspace ID
+	Provider     string            `json:"provider,omitempty"`     // agent provider: claude, echo, etc.
+	Supervise    bool              `json:"supervise,omitempty"`    // run the agent as a child process and offer follow-ups when it exits
+	MaxIssues    int               `json:"maxIssues,omitempty"`    // upper bound on issues fetched across pages
+	LinearAPIURL string            `json:"linearApiUrl,omitempty"` // override the Linear GraphQL endpoint
+	Sync         *SyncConfig       `json:"sync,omitempty"`         // background refresh of the issue list
+
+	Providers       map[string]ProviderConfig `json:"providers,omitempty"`       // custom providers by ID
+	PromptTemplates *PromptTemplatesConfig    `json:"promptTemplates,omitempty"` // prompt template selection
+	Aider           *AiderConfig              `json:"aider,omitempty"`           // aider provider options
+	LinearMCP       *LinearMCPConfig          `json:"linearMcp,omitempty"`       // Linear MCP server handed to agents
+	WorktreeRoot    string                    `json:"worktreeRoot,omitempty"`    // where per-issue git worktrees are created
+	BaseBranches    map[string]string         `json:"baseBranches,omitempty"`    // repository path -> branch new issue branches start from
+	BranchNames     *BranchNamesConfig        `json:"branchNames,omitempty"`     // issue branch naming scheme
 }
 
+// BranchNamesConfig names issue branches after a template, e.g.
+// "{{.Type}}/{{.Identifier}}-{{.Slug}}", instead of Linear's suggested branch
+// name. The most specific template wins: repository, then workspace, then
+// the default.
+type BranchNamesConfig struct {
+	Template    string            `json:"template,omitempty"`
+	Workspaces  map[string]string `json:"workspaces,omitempty"`  // workspace ID -> template
+	Repos       map[string]string `json:"repos,omitempty"`       // repository path -> template
+	Types       map[string]string `json:"types,omitempty"`       // label name -> .Type, on top of the built-in ones
+	DefaultType string            `json:"defaultType,omitempty"` // .Type of issues without such a label
+}
+
+// LinearMCPConfig gives agents that accept an MCP config access to the Linear
+// MCP server, authenticated with the workspace's API key
+type LinearMCPConfig struct {
+	Enabled bool   `json:"enabled"`
+	URL     string `json:"url,omitempty"` // defaults to Linear's hosted server
+}
+
+// AiderConfig configures the aider provider
+type AiderConfig struct {
+	AddReferencedFiles bool   `json:"addReferencedFiles,omitempty"` // add repository files mentioned in the issue to the chat
+	PlanMode           string `json:"planMode,omitempty"`           // chat mode used for plan mode: ask (default) or architect
+}
+
+// Aider chat modes used for plan mode
+const (
+	AiderAsk       = "ask"
+	AiderArchitect = "architect"
+)
+
+// PromptTemplatesConfig selects the prompt template an issue is started with.
+// Values are template names, loaded from <name>.tmpl. The most specific
+// match wins: label, then team, then workspace, then the default.
+type PromptTemplatesConfig struct {
+	Default    string            `json:"default,omitempty"`
+	Workspaces map[string]string `json:"workspaces,omitempty"` // workspace ID -> template
+	Teams      map[string]string `json:"teams,omitempty"`      // team key -> template
+	Labels     map[string]string `json:"labels,omitempty"`     // label name -> template
+}
+
+// ProviderConfig declares a custom agent provider that runs a command.
+// Args and env values are Go templates with the fields .Prompt, .PromptFile,
+// .Identifier, .Title, .URL, .BranchName and .PlanMode.
+type ProviderConfig struct {
+	Name   string            `json:"name,omitempty"`   // display name, defaults to the ID
+	Binary string            `json:"binary"`           // executable on PATH or path to it
+	Args   []string          `json:"args,omitempty"`   // argument templates
+	Prompt string            `json:"prompt,omitempty"` // prompt delivery: arg (default), stdin or file
+	Env    map[string]string `json:"env,omitempty"`    // extra environment variables
+}
+
+// SyncConfig controls how linc picks up issue changes made elsewhere while it runs
+type SyncConfig struct {
+	Interval      int    `json:"interval,omitempty"`      // seconds between polls, negative to disable polling
+	WebhookListen string `json:"webhookListen,omitempty"` // local address to accept Linear webhooks on, e.g. 127.0.0.1:8787
+	WebhookSecret string `json:"webhookSecret,omitempty"` // signing secret used to verify webhook payloads
+}
+
+// DefaultSyncInterval is how often the issue list is polled for changes
+const DefaultSyncInterval = 60 * time.Second
+
+// LinearAPIURLEnv overrides the Linear GraphQL endpoint, taking precedence over config
+const LinearAPIURLEnv = "LINC_LINEAR_API_URL"
+
 func configDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -28,6 +106,11 @@ func configDir() (string, error) {
 	return filepath.Join(home, ".linc"), nil
 }
 
+// Dir returns linc's data directory, ~/.linc
+func Dir() (string, error) {
+	return configDir()
+}
+
 func configPath() (string, error) {
 	dir, err := configDir()
 	if err != nil {
@@ -192,3 +275,118 @@ func (c *Config) SetProvider(provider string) error {
 	c.Provider = provider
 	return c.Save()
 }
+
+// GetSyncInterval returns how often to poll for issue changes, or 0 if
+// polling is disabled
+func (c *Config) GetSyncInterval() time.Duration {
+	if c.Sync == nil || c.Sync.Interval == 0 {
+		return DefaultSyncInterval
+	}
+	if c.Sync.Interval < 0 {
+		return 0
+	}
+	return time.Duration(c.Sync.Interval) * time.Second
+}
+
+// GetWebhookListen returns the address of the local webhook listener and
+// its signing secret; the address is empty if webhooks are not configured
+func (c *Config) GetWebhookListen() (addr, secret string) {
+	if c.Sync == nil {
+		return "", ""
+	}
+	return c.Sync.WebhookListen, c.Sync.WebhookSecret
+}
+
+// LinearMCPEnabled reports whether agents get a Linear MCP server config, and
+// the server's URL, empty for the default
+func (c *Config) LinearMCPEnabled() (bool, string) {
+	if c.LinearMCP == nil {
+		return false, ""
+	}
+	return c.LinearMCP.Enabled, c.LinearMCP.URL
+}
+
+// GetWorktreeRoot returns the directory holding linc's worktrees of the
+// repository at repoRoot: <root>/<repository name>, with the root
+// defaulting to ~/.linc/worktrees
+func (c *Config) GetWorktreeRoot(repoRoot string) (string, error) {
+	root := c.WorktreeRoot
+	if root == "" {
+		dir, err := configDir()
+		if err != nil {
+			return "", err
+		}
+		root = filepath.Join(dir, "worktrees")
+	} else if rest, ok := strings.CutPrefix(root, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		root = filepath.Join(home, rest)
+	}
+	return filepath.Join(root, filepath.Base(repoRoot)), nil
+}
+
+// BaseBranchFor returns the branch new issue branches of the repository at
+// repoRoot start from, or empty string to use the remote's default branch
+func (c *Config) BaseBranchFor(repoRoot string) string {
+	if repoRoot == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(repoRoot); err == nil {
+		repoRoot = abs
+	}
+	return c.BaseBranches[repoRoot]
+}
+
+// BranchTemplateFor returns the branch name template for issues of a
+// workspace worked on in the repository at repoRoot, or empty string to use
+// Linear's branch names
+func (c *Config) BranchTemplateFor(workspaceID, repoRoot string) string {
+	t := c.BranchNames
+	if t == nil {
+		return ""
+	}
+	if repoRoot != "" {
+		if abs, err := filepath.Abs(repoRoot); err == nil {
+			repoRoot = abs
+		}
+		if text, ok := t.Repos[repoRoot]; ok {
+			return text
+		}
+	}
+	if text, ok := t.Workspaces[workspaceID]; ok {
+		return text
+	}
+	return t.Template
+}
+
+// GetLinearAPIURL returns the Linear GraphQL endpoint override from the
+// environment or config, or empty string to use the default endpoint
+func (c *Config) GetLinearAPIURL() string {
+	if url := os.Getenv(LinearAPIURLEnv); url != "" {
+		return url
+	}
+	return c.LinearAPIURL
+}
+
+// PromptTemplateFor returns the name of the prompt template for an issue, or
+// empty string to use the built-in default
+func (c *Config) PromptTemplateFor(workspaceID, teamKey string, labels []string) string {
+	t := c.PromptTemplates
+	if t == nil {
+		return ""
+	}
+	for _, label := range labels {
+		if name, ok := t.Labels[label]; ok {
+			return name
+		}
+	}
+	if name, ok := t.Teams[teamKey]; ok {
+		return name
+	}
+	if name, ok := t.Workspaces[workspaceID]; ok {
+		return name
+	}
+	return t.Default
+}
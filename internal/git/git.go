@@ -1,16 +1,55 @@
 package git
 
 import (
-	"os/exec"
+	"fmt"
 	"strings"
 )
 
 // GetCurrentBranch returns the current git branch name, or empty string if not in a git repo
+// or HEAD is detached
 func GetCurrentBranch() string {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	output, err := cmd.Output()
+	repo, err := Current()
 	if err != nil {
 		return ""
 	}
-	return strings.TrimSpace(string(output))
+	return repo.Branch()
+}
+
+// GetRepoRoot returns the top-level directory of the current git repository, or empty string if not in a git repo
+func GetRepoRoot() string {
+	repo, err := Current()
+	if err != nil {
+		return ""
+	}
+	return repo.Root
+}
+
+// GetHead returns the commit HEAD points to, or empty string if not in a git repo
+func GetHead() string {
+	repo, err := Current()
+	if err != nil {
+		return ""
+	}
+	return repo.Head()
+}
+
+// CommitsSince returns the one-line summaries of the commits reachable from
+// HEAD but not from rev, newest first
+func (r *Repo) CommitsSince(rev string) []string {
+	output, err := r.run("log", "--format=%h %s", rev+"..HEAD")
+	if err != nil || output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
+
+// Push pushes the branch to the repository's remote and sets it as the
+// branch's upstream
+func (r *Repo) Push(branch string) error {
+	remote := r.Remote()
+	if remote == "" {
+		return fmt.Errorf("no remote to push %s to", branch)
+	}
+	_, err := r.run("push", "--set-upstream", remote, branch)
+	return err
 }
@@ -0,0 +1,240 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Repo is a git working tree, operated on by running git in its root
+type Repo struct {
+	Root string // top-level directory of the working tree
+}
+
+// Open returns the repository whose working tree contains dir
+func Open(dir string) (*Repo, error) {
+	cmd := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository", dir)
+	}
+	return &Repo{Root: strings.TrimSpace(string(output))}, nil
+}
+
+// Current returns the repository containing the working directory
+func Current() (*Repo, error) {
+	return Open(".")
+}
+
+// run runs git in the repository and returns its trimmed output; errors
+// carry what git printed
+func (r *Repo) run(args ...string) (string, error) {
+	output, err := r.output(args...)
+	return strings.TrimSpace(output), err
+}
+
+// output runs git in the repository and returns its output as is, for
+// formats where leading whitespace matters
+func (r *Repo) output(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.Root}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+	return string(output), nil
+}
+
+// ok runs git in the repository and reports whether it succeeded
+func (r *Repo) ok(args ...string) bool {
+	_, err := r.run(args...)
+	return err == nil
+}
+
+// Branch returns the checked out branch, or empty string if HEAD is detached
+func (r *Repo) Branch() string {
+	branch, err := r.run("symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return branch
+}
+
+// Head returns the commit HEAD points to, or empty string if there is none yet
+func (r *Repo) Head() string {
+	head, err := r.run("rev-parse", "--verify", "--quiet", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return head
+}
+
+// BranchHead returns the commit a local branch points to, or empty string if
+// there is no such branch
+func (r *Repo) BranchHead(branch string) string {
+	head, err := r.run("rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	if err != nil {
+		return ""
+	}
+	return head
+}
+
+// Remotes returns the names of the repository's remotes
+func (r *Repo) Remotes() ([]string, error) {
+	output, err := r.run("remote")
+	if err != nil {
+		return nil, err
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// Remote returns the remote branches are fetched from and pushed to: the
+// current branch's, else origin, else the only remote. It is empty when
+// there is no remote or no way to choose one.
+func (r *Repo) Remote() string {
+	if branch := r.Branch(); branch != "" {
+		if remote, err := r.run("config", "branch."+branch+".remote"); err == nil && remote != "." {
+			return remote
+		}
+	}
+	remotes, err := r.Remotes()
+	if err != nil {
+		return ""
+	}
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return remote
+		}
+	}
+	if len(remotes) == 1 {
+		return remotes[0]
+	}
+	return ""
+}
+
+// Fetch updates the remote-tracking branches of remote, pruning deleted ones
+func (r *Repo) Fetch(remote string) error {
+	_, err := r.run("fetch", "--prune", remote)
+	return err
+}
+
+// LocalBranchExists reports whether the branch exists locally
+func (r *Repo) LocalBranchExists(branch string) bool {
+	return r.ok("show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+}
+
+// RemoteBranchExists reports whether the branch exists on remote, as of the
+// last fetch
+func (r *Repo) RemoteBranchExists(remote, branch string) bool {
+	return r.ok("show-ref", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch)
+}
+
+// HasBranch reports whether the branch exists locally or, if remote is set,
+// on remote as of the last fetch
+func (r *Repo) HasBranch(remote, branch string) bool {
+	return r.LocalBranchExists(branch) || (remote != "" && r.RemoteBranchExists(remote, branch))
+}
+
+// Checkout switches the working tree to an existing local branch
+func (r *Repo) Checkout(branch string) error {
+	_, err := r.run("checkout", branch)
+	return err
+}
+
+// CreateBranch creates a branch at start, HEAD if empty, and checks it out
+func (r *Repo) CreateBranch(branch, start string) error {
+	args := []string{"checkout", "--no-track", "-b", branch}
+	if start != "" {
+		args = append(args, start)
+	}
+	_, err := r.run(args...)
+	return err
+}
+
+// TrackBranch creates a local branch from the branch of the same name on
+// remote, set to track it, and checks it out
+func (r *Repo) TrackBranch(remote, branch string) error {
+	_, err := r.run("checkout", "--track", "-b", branch, remote+"/"+branch)
+	return err
+}
+
+// Upstream returns the branch's upstream, e.g. "origin/main", or empty
+// string if it tracks none
+func (r *Repo) Upstream(branch string) string {
+	upstream, err := r.run("rev-parse", "--abbrev-ref", "--symbolic-full-name", branch+"@{upstream}")
+	if err != nil {
+		return ""
+	}
+	return upstream
+}
+
+// AheadBehind counts the commits on branch that upstream doesn't have, and
+// the commits on upstream that branch doesn't have
+func (r *Repo) AheadBehind(branch, upstream string) (ahead, behind int, err error) {
+	output, err := r.run("rev-list", "--left-right", "--count", branch+"..."+upstream)
+	if err != nil {
+		return 0, 0, err
+	}
+	counts := strings.Fields(output)
+	if len(counts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected git rev-list output: %q", output)
+	}
+	if ahead, err = strconv.Atoi(counts[0]); err != nil {
+		return 0, 0, err
+	}
+	if behind, err = strconv.Atoi(counts[1]); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// DefaultBranch returns the default branch of remote, e.g. "main", from the
+// remote's HEAD as recorded by clone or `git remote set-head`. Without one,
+// it falls back to a main or master branch on the remote, then locally.
+func (r *Repo) DefaultBranch(remote string) (string, error) {
+	if remote != "" {
+		if ref, err := r.run("symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD"); err == nil {
+			return strings.TrimPrefix(ref, remote+"/"), nil
+		}
+	}
+	for _, branch := range []string{"main", "master"} {
+		if remote != "" && r.RemoteBranchExists(remote, branch) {
+			return branch, nil
+		}
+	}
+	for _, branch := range []string{"main", "master"} {
+		if r.LocalBranchExists(branch) {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("can't tell the default branch of %s", r.Root)
+}
+
+// BaseRef returns the ref new branches start from: branch, or the remote's
+// default branch if empty, preferring the remote's copy so the new branch
+// starts from what was last fetched, e.g. "origin/main"
+func (r *Repo) BaseRef(remote, branch string) (string, error) {
+	if branch == "" {
+		var err error
+		if branch, err = r.DefaultBranch(remote); err != nil {
+			return "", err
+		}
+	}
+	if remote != "" && r.RemoteBranchExists(remote, branch) {
+		return remote + "/" + branch, nil
+	}
+	if r.LocalBranchExists(branch) {
+		return branch, nil
+	}
+	return "", fmt.Errorf("no branch %s to start from", branch)
+}
@@ -0,0 +1,337 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitEnv isolates git from the user's configuration and gives it an identity
+// to commit with
+func gitEnv(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+}
+
+// gitIn runs git in dir and returns its trimmed output
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// commit writes a file and commits it
+func commit(t *testing.T, dir, file, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "add", file)
+	gitIn(t, dir, "commit", "--quiet", "--message", "Change "+file)
+}
+
+// newRepo creates a repository on branch main with one commit
+func newRepo(t *testing.T) *Repo {
+	t.Helper()
+	gitEnv(t)
+	dir := t.TempDir()
+	gitIn(t, dir, "init", "--quiet", "--initial-branch", "main")
+	commit(t, dir, "README", "hello\n")
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+// newClone creates a bare repository with default branch trunk and returns a
+// clone of it, whose remote is origin
+func newClone(t *testing.T) (clone *Repo, remoteDir string) {
+	t.Helper()
+	seed := newRepo(t)
+	gitIn(t, seed.Root, "branch", "--move", "main", "trunk")
+	remoteDir = filepath.Join(t.TempDir(), "remote.git")
+	gitIn(t, seed.Root, "clone", "--quiet", "--bare", seed.Root, remoteDir)
+
+	dir := filepath.Join(t.TempDir(), "clone")
+	gitIn(t, t.TempDir(), "clone", "--quiet", remoteDir, dir)
+	clone, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clone, remoteDir
+}
+
+func TestOpenOutsideRepository(t *testing.T) {
+	gitEnv(t)
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Fatal("Open outside a repository succeeded")
+	}
+}
+
+func TestBranchAndHead(t *testing.T) {
+	repo := newRepo(t)
+	if got := repo.Branch(); got != "main" {
+		t.Errorf("Branch() = %q, want main", got)
+	}
+	head := repo.Head()
+	if head != gitIn(t, repo.Root, "rev-parse", "HEAD") {
+		t.Errorf("Head() = %q", head)
+	}
+
+	if got := repo.BranchHead("main"); got != head {
+		t.Errorf("BranchHead(main) = %q, want %q", got, head)
+	}
+	if got := repo.BranchHead("missing"); got != "" {
+		t.Errorf("BranchHead(missing) = %q, want empty", got)
+	}
+
+	gitIn(t, repo.Root, "checkout", "--quiet", "--detach")
+	if got := repo.Branch(); got != "" {
+		t.Errorf("Branch() detached = %q, want empty", got)
+	}
+	if got := repo.Head(); got != head {
+		t.Errorf("Head() detached = %q, want %q", got, head)
+	}
+}
+
+func TestHeadWithoutCommits(t *testing.T) {
+	gitEnv(t)
+	dir := t.TempDir()
+	gitIn(t, dir, "init", "--quiet", "--initial-branch", "main")
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.Head(); got != "" {
+		t.Errorf("Head() = %q, want empty", got)
+	}
+}
+
+func TestRemote(t *testing.T) {
+	repo := newRepo(t)
+	if got := repo.Remote(); got != "" {
+		t.Errorf("Remote() without remotes = %q, want empty", got)
+	}
+
+	gitIn(t, repo.Root, "remote", "add", "upstream", repo.Root)
+	if got := repo.Remote(); got != "upstream" {
+		t.Errorf("Remote() with one remote = %q, want upstream", got)
+	}
+
+	gitIn(t, repo.Root, "remote", "add", "fork", repo.Root)
+	if got := repo.Remote(); got != "" {
+		t.Errorf("Remote() with two remotes = %q, want empty", got)
+	}
+
+	gitIn(t, repo.Root, "remote", "add", "origin", repo.Root)
+	if got := repo.Remote(); got != "origin" {
+		t.Errorf("Remote() with origin = %q, want origin", got)
+	}
+
+	// The current branch's remote wins
+	gitIn(t, repo.Root, "config", "branch.main.remote", "fork")
+	if got := repo.Remote(); got != "fork" {
+		t.Errorf("Remote() with branch remote = %q, want fork", got)
+	}
+
+	remotes, err := repo.Remotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remotes) != 3 {
+		t.Errorf("Remotes() = %v, want 3 remotes", remotes)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	clone, remoteDir := newClone(t)
+
+	other := filepath.Join(t.TempDir(), "other")
+	gitIn(t, t.TempDir(), "clone", "--quiet", remoteDir, other)
+	gitIn(t, other, "checkout", "--quiet", "-b", "feature")
+	commit(t, other, "feature.txt", "feature\n")
+	gitIn(t, other, "push", "--quiet", "origin", "feature")
+
+	if clone.RemoteBranchExists("origin", "feature") {
+		t.Fatal("feature exists before fetching")
+	}
+	if err := clone.Fetch("origin"); err != nil {
+		t.Fatal(err)
+	}
+	if !clone.RemoteBranchExists("origin", "feature") {
+		t.Fatal("feature missing after fetching")
+	}
+	if !clone.HasBranch("origin", "feature") || clone.HasBranch("", "feature") {
+		t.Error("HasBranch() disagrees with the fetched remote branch")
+	}
+
+	// Branches deleted on the remote are pruned
+	gitIn(t, other, "push", "--quiet", "origin", "--delete", "feature")
+	if err := clone.Fetch("origin"); err != nil {
+		t.Fatal(err)
+	}
+	if clone.RemoteBranchExists("origin", "feature") {
+		t.Error("feature still exists after it was deleted and fetched")
+	}
+
+	if err := clone.Fetch("nowhere"); err == nil {
+		t.Error("Fetch of a missing remote succeeded")
+	}
+}
+
+func TestBranches(t *testing.T) {
+	clone, _ := newClone(t)
+
+	if !clone.LocalBranchExists("trunk") || clone.LocalBranchExists("feature") {
+		t.Fatal("LocalBranchExists disagrees with the clone's branches")
+	}
+
+	if err := clone.CreateBranch("feature", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := clone.Branch(); got != "feature" {
+		t.Errorf("Branch() after CreateBranch = %q, want feature", got)
+	}
+	if got := clone.Upstream("feature"); got != "" {
+		t.Errorf("Upstream(feature) = %q, want none", got)
+	}
+
+	if err := clone.Checkout("trunk"); err != nil {
+		t.Fatal(err)
+	}
+	if err := clone.Checkout("missing"); err == nil {
+		t.Error("Checkout of a missing branch succeeded")
+	}
+
+	gitIn(t, clone.Root, "push", "--quiet", "origin", "feature")
+	gitIn(t, clone.Root, "branch", "--delete", "feature")
+	if err := clone.TrackBranch("origin", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	if got := clone.Upstream("feature"); got != "origin/feature" {
+		t.Errorf("Upstream(feature) after TrackBranch = %q, want origin/feature", got)
+	}
+}
+
+func TestAheadBehind(t *testing.T) {
+	clone, _ := newClone(t)
+	upstream := clone.Upstream("trunk")
+	if upstream != "origin/trunk" {
+		t.Fatalf("Upstream(trunk) = %q, want origin/trunk", upstream)
+	}
+
+	ahead, behind, err := clone.AheadBehind("trunk", upstream)
+	if err != nil || ahead != 0 || behind != 0 {
+		t.Errorf("AheadBehind() = %d, %d, %v, want 0, 0", ahead, behind, err)
+	}
+
+	commit(t, clone.Root, "a.txt", "a\n")
+	commit(t, clone.Root, "b.txt", "b\n")
+	gitIn(t, clone.Root, "update-ref", "refs/remotes/origin/trunk", "HEAD~2")
+	gitIn(t, clone.Root, "checkout", "--quiet", "--detach", "origin/trunk")
+	commit(t, clone.Root, "c.txt", "c\n")
+	gitIn(t, clone.Root, "update-ref", "refs/remotes/origin/trunk", "HEAD")
+
+	ahead, behind, err = clone.AheadBehind("trunk", upstream)
+	if err != nil || ahead != 2 || behind != 1 {
+		t.Errorf("AheadBehind() = %d, %d, %v, want 2, 1", ahead, behind, err)
+	}
+
+	if _, _, err := clone.AheadBehind("trunk", "origin/missing"); err == nil {
+		t.Error("AheadBehind() against a missing upstream succeeded")
+	}
+}
+
+func TestDefaultBranchAndBaseRef(t *testing.T) {
+	clone, _ := newClone(t)
+
+	// From the remote's HEAD, recorded by clone
+	branch, err := clone.DefaultBranch("origin")
+	if err != nil || branch != "trunk" {
+		t.Errorf("DefaultBranch(origin) = %q, %v, want trunk", branch, err)
+	}
+	base, err := clone.BaseRef("origin", "")
+	if err != nil || base != "origin/trunk" {
+		t.Errorf("BaseRef(origin, \"\") = %q, %v, want origin/trunk", base, err)
+	}
+
+	// A configured branch only the clone has
+	gitIn(t, clone.Root, "branch", "develop")
+	base, err = clone.BaseRef("origin", "develop")
+	if err != nil || base != "develop" {
+		t.Errorf("BaseRef(origin, develop) = %q, %v, want develop", base, err)
+	}
+	if _, err := clone.BaseRef("origin", "missing"); err == nil {
+		t.Error("BaseRef of a missing branch succeeded")
+	}
+
+	// Without the remote's HEAD, main on the remote
+	gitIn(t, clone.Root, "remote", "set-head", "origin", "--delete")
+	gitIn(t, clone.Root, "update-ref", "refs/remotes/origin/main", "HEAD")
+	branch, err = clone.DefaultBranch("origin")
+	if err != nil || branch != "main" {
+		t.Errorf("DefaultBranch(origin) without HEAD = %q, %v, want main", branch, err)
+	}
+
+	// Without a remote, a local main or master
+	repo := newRepo(t)
+	gitIn(t, repo.Root, "branch", "--move", "main", "master")
+	branch, err = repo.DefaultBranch("")
+	if err != nil || branch != "master" {
+		t.Errorf("DefaultBranch(\"\") = %q, %v, want master", branch, err)
+	}
+	base, err = repo.BaseRef("", "")
+	if err != nil || base != "master" {
+		t.Errorf("BaseRef(\"\", \"\") = %q, %v, want master", base, err)
+	}
+
+	gitIn(t, repo.Root, "branch", "--move", "master", "trunk")
+	if _, err := repo.DefaultBranch(""); err == nil {
+		t.Error("DefaultBranch() without main or master succeeded")
+	}
+}
+
+func TestCommitsSinceAndPush(t *testing.T) {
+	clone, remoteDir := newClone(t)
+	head := clone.Head()
+	if commits := clone.CommitsSince(head); commits != nil {
+		t.Errorf("CommitsSince(HEAD) = %v, want none", commits)
+	}
+
+	if err := clone.CreateBranch("feature", ""); err != nil {
+		t.Fatal(err)
+	}
+	commit(t, clone.Root, "a.txt", "a\n")
+	commit(t, clone.Root, "b.txt", "b\n")
+	commits := clone.CommitsSince(head)
+	if len(commits) != 2 || !strings.HasSuffix(commits[0], " Change b.txt") {
+		t.Errorf("CommitsSince() = %v, want b.txt's then a.txt's", commits)
+	}
+
+	if err := clone.Push("feature"); err != nil {
+		t.Fatal(err)
+	}
+	if got := clone.Upstream("feature"); got != "origin/feature" {
+		t.Errorf("Upstream(feature) after Push = %q, want origin/feature", got)
+	}
+	if got := gitIn(t, remoteDir, "rev-parse", "feature"); got != clone.Head() {
+		t.Errorf("remote feature = %s, want %s", got, clone.Head())
+	}
+
+	if err := newRepo(t).Push("main"); err == nil {
+		t.Error("Push without a remote succeeded")
+	}
+}
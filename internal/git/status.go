@@ -0,0 +1,145 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Status summarizes the uncommitted state of the working tree
+type Status struct {
+	Staged     int    // files with staged changes
+	Modified   int    // tracked files with unstaged changes
+	Untracked  int    // files git doesn't track yet
+	Conflicted int    // files with unresolved conflicts
+	Operation  string // merge, rebase, cherry-pick or revert in progress, empty if none
+}
+
+// Dirty reports whether switching branches would have to deal with local
+// changes or an unfinished operation
+func (s Status) Dirty() bool {
+	return s.Staged+s.Modified+s.Untracked+s.Conflicted > 0 || s.Operation != ""
+}
+
+// String describes the changes, e.g. "2 modified, 1 untracked"
+func (s Status) String() string {
+	var parts []string
+	if s.Operation != "" {
+		parts = append(parts, s.Operation+" in progress")
+	}
+	for _, count := range []struct {
+		n    int
+		name string
+	}{
+		{s.Conflicted, "conflicted"},
+		{s.Staged, "staged"},
+		{s.Modified, "modified"},
+		{s.Untracked, "untracked"},
+	} {
+		if count.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.name))
+		}
+	}
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Status returns the state of the working tree
+func (r *Repo) Status() (*Status, error) {
+	output, err := r.output("status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+
+	var status Status
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		x, y := line[0], line[1]
+		switch {
+		case x == '?':
+			status.Untracked++
+		case x == 'U' || y == 'U' || (x == 'A' && y == 'A') || (x == 'D' && y == 'D'):
+			status.Conflicted++
+		default:
+			if x != ' ' {
+				status.Staged++
+			}
+			if y != ' ' {
+				status.Modified++
+			}
+		}
+	}
+	status.Operation = r.operationInProgress()
+	return &status, nil
+}
+
+// operationInProgress returns the multi-step operation the working tree is
+// in the middle of, or empty string if none
+func (r *Repo) operationInProgress() string {
+	operations := []struct {
+		path, name string
+	}{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+	}
+	args := []string{"rev-parse"}
+	for _, op := range operations {
+		args = append(args, "--git-path", op.path)
+	}
+	output, err := r.run(args...)
+	if err != nil {
+		return ""
+	}
+	for i, path := range strings.Split(output, "\n") {
+		if i >= len(operations) {
+			break
+		}
+		// Paths are relative to the root git ran in
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.Root, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return operations[i].name
+		}
+	}
+	return ""
+}
+
+// Stash stashes the working tree's changes, untracked files included, under
+// message
+func (r *Repo) Stash(message string) error {
+	_, err := r.run("stash", "push", "--include-untracked", "--message", message)
+	return err
+}
+
+// FindStash returns the ref of the newest stash made with message, e.g.
+// "stash@{1}", or empty string if there is none
+func (r *Repo) FindStash(message string) string {
+	output, err := r.run("stash", "list", "--format=%gd %gs")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(output, "\n") {
+		ref, subject, ok := strings.Cut(line, " ")
+		// Stashes made with a message are described "On <branch>: <message>"
+		if ok && strings.HasSuffix(subject, ": "+message) {
+			return ref
+		}
+	}
+	return ""
+}
+
+// PopStash applies the stash at ref to the working tree and drops it. The
+// stash is kept if applying it conflicts.
+func (r *Repo) PopStash(ref string) error {
+	_, err := r.run("stash", "pop", ref)
+	return err
+}
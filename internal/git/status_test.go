@@ -0,0 +1,116 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	repo := newRepo(t)
+	status, err := repo.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Dirty() || status.String() != "clean" {
+		t.Errorf("Status() of a clean tree = %+v", status)
+	}
+
+	commit(t, repo.Root, "staged.txt", "one\n")
+	if err := os.WriteFile(filepath.Join(repo.Root, "staged.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, repo.Root, "add", "staged.txt")
+	// The first line of git's output starts with a space here
+	if err := os.WriteFile(filepath.Join(repo.Root, "README"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo.Root, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err = repo.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Status{Staged: 1, Modified: 1, Untracked: 1}
+	if *status != want {
+		t.Errorf("Status() = %+v, want %+v", *status, want)
+	}
+	if got := status.String(); got != "1 staged, 1 modified, 1 untracked" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestStatusMergeConflict(t *testing.T) {
+	repo := newRepo(t)
+	gitIn(t, repo.Root, "checkout", "--quiet", "-b", "other")
+	commit(t, repo.Root, "README", "other\n")
+	gitIn(t, repo.Root, "checkout", "--quiet", "main")
+	commit(t, repo.Root, "README", "main\n")
+
+	// The merge is expected to fail with a conflict
+	_ = exec.Command("git", "-C", repo.Root, "merge", "other").Run()
+
+	status, err := repo.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Conflicted != 1 || status.Operation != "merge" {
+		t.Errorf("Status() = %+v, want 1 conflicted and a merge in progress", *status)
+	}
+	if !status.Dirty() {
+		t.Error("Dirty() = false during a merge")
+	}
+}
+
+func TestStashFindAndPop(t *testing.T) {
+	repo := newRepo(t)
+	if ref := repo.FindStash("linc: local changes on main"); ref != "" {
+		t.Errorf("FindStash() without stashes = %q", ref)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo.Root, "untracked.txt"), []byte("mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Stash("linc: local changes on main"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := repo.Status(); status.Dirty() {
+		t.Errorf("tree is %s after stashing, want clean", status)
+	}
+
+	// A newer stash with another message moves ours down the list
+	if err := os.WriteFile(filepath.Join(repo.Root, "README"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Stash("something else"); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := repo.FindStash("linc: local changes on main")
+	if ref != "stash@{1}" {
+		t.Fatalf("FindStash() = %q, want stash@{1}", ref)
+	}
+	if ref := repo.FindStash("linc: local changes on other"); ref != "" {
+		t.Errorf("FindStash() for another branch = %q, want empty", ref)
+	}
+
+	if err := repo.PopStash(ref); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(repo.Root, "untracked.txt")); err != nil {
+		t.Errorf("untracked file not restored: %v", err)
+	}
+	if ref := repo.FindStash("linc: local changes on main"); ref != "" {
+		t.Errorf("FindStash() after popping = %q, want empty", ref)
+	}
+	if ref := repo.FindStash("something else"); ref != "stash@{0}" {
+		t.Errorf("FindStash() of the other stash = %q, want stash@{0}", ref)
+	}
+
+	if err := repo.PopStash("stash@{5}"); err == nil {
+		t.Error("PopStash() of a missing stash succeeded")
+	}
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is a working tree of a repository
+type Worktree struct {
+	Path   string
+	Branch string // empty when detached
+	Head   string
+}
+
+// GetMainRepoRoot returns the top-level directory of the current
+// repository's main working tree, or empty string if not in a git repo
+func GetMainRepoRoot() string {
+	repo, err := Current()
+	if err != nil {
+		return ""
+	}
+	root, err := repo.MainRoot()
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+// MainRoot returns the top-level directory of the repository's main working
+// tree, which is Root unless r is a linked worktree
+func (r *Repo) MainRoot() (string, error) {
+	commonDir, err := r.run("rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+
+	// A submodule's git dir lives in its superproject's .git/modules and
+	// records where its working tree is
+	if worktree, err := r.run("--git-dir", commonDir, "config", "core.worktree"); err == nil {
+		if !filepath.IsAbs(worktree) {
+			worktree = filepath.Join(commonDir, worktree)
+		}
+		return filepath.Clean(worktree), nil
+	}
+	return filepath.Dir(commonDir), nil
+}
+
+// AddWorktree checks out branch in a new worktree at path, reusing the
+// worktree if path already is one. When missing locally, the branch is
+// created, tracking the remote's if that exists, else starting at start,
+// HEAD if empty.
+func (r *Repo) AddWorktree(path, branch, start string) (reused bool, err error) {
+	worktrees, err := r.Worktrees()
+	if err != nil {
+		return false, err
+	}
+	for _, wt := range worktrees {
+		if samePath(wt.Path, path) {
+			return true, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+
+	remote := r.Remote()
+	switch {
+	case r.LocalBranchExists(branch):
+		_, err = r.run("worktree", "add", path, branch)
+	case remote != "" && r.RemoteBranchExists(remote, branch):
+		_, err = r.run("worktree", "add", "--track", "-b", branch, path, remote+"/"+branch)
+	default:
+		args := []string{"worktree", "add", "--no-track", "-b", branch, path}
+		if start != "" {
+			args = append(args, start)
+		}
+		_, err = r.run(args...)
+	}
+	return false, err
+}
+
+// Worktrees returns the working trees of the repository, the main one first
+func (r *Repo) Worktrees() ([]Worktree, error) {
+	output, err := r.run("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+
+	var worktrees []Worktree
+	for _, block := range strings.Split(output, "\n\n") {
+		var wt Worktree
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				wt.Path = value
+			case "HEAD":
+				wt.Head = value
+			case "branch":
+				wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		}
+		if wt.Path != "" {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	return worktrees, nil
+}
+
+// RemoveWorktree removes a worktree; git refuses if it has uncommitted or
+// untracked changes
+func (r *Repo) RemoveWorktree(path string) error {
+	_, err := r.run("worktree", "remove", path)
+	return err
+}
+
+// PruneWorktrees drops the records of worktrees whose directory is gone
+func (r *Repo) PruneWorktrees() error {
+	_, err := r.run("worktree", "prune")
+	return err
+}
+
+// samePath reports whether a and b name the same directory, resolving
+// symlinks such as macOS's /tmp
+func samePath(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
+
+// WorktreeDir returns the directory of a branch's worktree under root
+func WorktreeDir(root, branch string) string {
+	return filepath.Join(root, strings.ReplaceAll(branch, "/", "-"))
+}
@@ -0,0 +1,92 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorktrees(t *testing.T) {
+	repo := newRepo(t)
+	path := WorktreeDir(filepath.Join(t.TempDir(), "worktrees"), "feat/eng-1")
+	if filepath.Base(path) != "feat-eng-1" {
+		t.Errorf("WorktreeDir() = %q, want a feat-eng-1 directory", path)
+	}
+
+	reused, err := repo.AddWorktree(path, "feat/eng-1", "")
+	if err != nil || reused {
+		t.Fatalf("AddWorktree() = %v, %v, want a new worktree", reused, err)
+	}
+	reused, err = repo.AddWorktree(path, "feat/eng-1", "")
+	if err != nil || !reused {
+		t.Fatalf("AddWorktree() again = %v, %v, want it reused", reused, err)
+	}
+
+	worktrees, err := repo.Worktrees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 2 || !samePath(worktrees[0].Path, repo.Root) {
+		t.Fatalf("Worktrees() = %+v, want the main one then the new one", worktrees)
+	}
+	if wt := worktrees[1]; !samePath(wt.Path, path) || wt.Branch != "feat/eng-1" || wt.Head != repo.Head() {
+		t.Errorf("new worktree = %+v", wt)
+	}
+
+	// From inside the linked worktree, the main one is still found
+	linked, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root, err := linked.MainRoot(); err != nil || !samePath(root, repo.Root) {
+		t.Errorf("MainRoot() in a linked worktree = %q, %v, want %q", root, err, repo.Root)
+	}
+
+	// Uncommitted changes keep a worktree
+	if err := os.WriteFile(filepath.Join(path, "wip.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RemoveWorktree(path); err == nil {
+		t.Error("RemoveWorktree() with untracked changes succeeded")
+	}
+	if err := os.Remove(filepath.Join(path, "wip.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RemoveWorktree(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Records of worktrees deleted by hand are pruned
+	other := filepath.Join(t.TempDir(), "other")
+	if _, err := repo.AddWorktree(other, "other", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.PruneWorktrees(); err != nil {
+		t.Fatal(err)
+	}
+	if worktrees, _ := repo.Worktrees(); len(worktrees) != 1 {
+		t.Errorf("Worktrees() after pruning = %+v, want only the main one", worktrees)
+	}
+}
+
+func TestMainRootInSubmodule(t *testing.T) {
+	sub := newRepo(t)
+	super := newRepo(t)
+	gitIn(t, super.Root, "-c", "protocol.file.allow=always", "submodule", "--quiet", "add", sub.Root, "sub")
+
+	if root, err := super.MainRoot(); err != nil || !samePath(root, super.Root) {
+		t.Errorf("MainRoot() of the superproject = %q, %v, want %q", root, err, super.Root)
+	}
+
+	submodule, err := Open(filepath.Join(super.Root, "sub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(super.Root, "sub")
+	if root, err := submodule.MainRoot(); err != nil || !samePath(root, want) {
+		t.Errorf("MainRoot() of the submodule = %q, %v, want %q", root, err, want)
+	}
+}
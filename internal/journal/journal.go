@@ -0,0 +1,190 @@
+// Package journal keeps Linear mutations made while offline on disk until
+// they can be replayed against the API.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"linc/internal/config"
+)
+
+// Kind identifies the mutation a journal entry replays
+type Kind string
+
+const (
+	KindTitle    Kind = "title"
+	KindPriority Kind = "priority"
+	KindState    Kind = "state"
+	KindComment  Kind = "comment"
+)
+
+// Mutation is a queued change to a Linear issue
+type Mutation struct {
+	ID         string    `json:"id"`
+	Kind       Kind      `json:"kind"`
+	IssueID    string    `json:"issueId"`
+	Identifier string    `json:"identifier,omitempty"`
+	QueuedAt   time.Time `json:"queuedAt"`
+
+	// BaseUpdatedAt is the issue's updatedAt when the change was made. The
+	// change conflicts if the issue has been updated on Linear since.
+	BaseUpdatedAt string `json:"baseUpdatedAt,omitempty"`
+
+	Title    string `json:"title,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+	StateID  string `json:"stateId,omitempty"`
+	Body     string `json:"body,omitempty"`
+}
+
+// String describes the mutation for status messages
+func (m Mutation) String() string {
+	issue := m.Identifier
+	if issue == "" {
+		issue = m.IssueID
+	}
+	switch m.Kind {
+	case KindTitle:
+		return "rename " + issue
+	case KindPriority:
+		return "priority of " + issue
+	case KindState:
+		return "status of " + issue
+	case KindComment:
+		return "comment on " + issue
+	}
+	return string(m.Kind) + " " + issue
+}
+
+// Journal is the durable queue of a workspace's pending mutations, stored
+// in ~/.linc/journal/<workspace ID>.json. It is safe for concurrent use.
+type Journal struct {
+	mu        sync.Mutex
+	path      string
+	mutations []Mutation
+	lastID    int64
+}
+
+// Open loads the journal of a workspace
+func Open(workspaceID string) (*Journal, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{path: filepath.Join(dir, "journal", workspaceID+".json")}
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return j, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &j.mutations); err != nil {
+		return nil, fmt.Errorf("failed to read journal %s: %w", j.path, err)
+	}
+	return j, nil
+}
+
+// Len returns the number of pending mutations
+func (j *Journal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.mutations)
+}
+
+// Pending returns the pending mutations in the order they were queued
+func (j *Journal) Pending() []Mutation {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]Mutation(nil), j.mutations...)
+}
+
+// Append queues a mutation and writes the journal to disk
+func (j *Journal) Append(m Mutation) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if m.QueuedAt.IsZero() {
+		m.QueuedAt = time.Now()
+	}
+	if m.ID == "" {
+		id := m.QueuedAt.UnixNano()
+		if id <= j.lastID {
+			id = j.lastID + 1
+		}
+		j.lastID = id
+		m.ID = strconv.FormatInt(id, 36)
+	}
+
+	mutations := append(j.mutations, m)
+	if err := j.save(mutations); err != nil {
+		return err
+	}
+	j.mutations = mutations
+	return nil
+}
+
+// Remove drops a mutation once it has been replayed or discarded
+func (j *Journal) Remove(id string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	mutations := make([]Mutation, 0, len(j.mutations))
+	for _, m := range j.mutations {
+		if m.ID != id {
+			mutations = append(mutations, m)
+		}
+	}
+	if err := j.save(mutations); err != nil {
+		return err
+	}
+	j.mutations = mutations
+	return nil
+}
+
+// save replaces the journal file atomically, syncing it to disk first so
+// queued changes survive a crash
+func (j *Journal) save(mutations []Mutation) error {
+	if len(mutations) == 0 {
+		if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	dir := filepath.Dir(j.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(mutations, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(j.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), j.path)
+}
@@ -0,0 +1,74 @@
+package journal
+
+import (
+	"os"
+	"testing"
+)
+
+func TestAppendAndRemove(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	j, err := Open("ws-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Len() != 0 {
+		t.Fatalf("new journal has %d mutations", j.Len())
+	}
+
+	for _, title := range []string{"First", "Second", "Third"} {
+		if err := j.Append(Mutation{Kind: KindTitle, IssueID: "issue-1", Title: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pending := j.Pending()
+	if pending[0].ID == "" || pending[0].ID == pending[1].ID || pending[1].ID == pending[2].ID {
+		t.Errorf("mutations queued together got IDs %q, %q, %q, want distinct ones", pending[0].ID, pending[1].ID, pending[2].ID)
+	}
+	if pending[0].QueuedAt.IsZero() {
+		t.Error("QueuedAt wasn't set")
+	}
+
+	// The queue survives a restart, in order
+	reopened, err := Open("ws-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reopened.Pending()
+	if len(got) != 3 || got[0].Title != "First" || got[2].Title != "Third" {
+		t.Fatalf("reopened journal = %+v, want the 3 mutations in order", got)
+	}
+
+	if err := reopened.Remove(got[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Pending(); len(got) != 2 || got[1].Title != "Third" {
+		t.Errorf("after removing the second = %+v", got)
+	}
+
+	// The file goes once nothing is queued
+	for _, m := range reopened.Pending() {
+		if err := reopened.Remove(m.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(reopened.path); !os.IsNotExist(err) {
+		t.Errorf("journal file still exists when empty: %v", err)
+	}
+}
+
+func TestMutationString(t *testing.T) {
+	tests := []struct {
+		mutation Mutation
+		want     string
+	}{
+		{Mutation{Kind: KindTitle, IssueID: "issue-1", Identifier: "ENG-1"}, "rename ENG-1"},
+		{Mutation{Kind: KindPriority, IssueID: "issue-1", Identifier: "ENG-1"}, "priority of ENG-1"},
+		{Mutation{Kind: KindState, IssueID: "issue-1"}, "status of issue-1"},
+		{Mutation{Kind: KindComment, IssueID: "issue-1", Identifier: "ENG-1"}, "comment on ENG-1"},
+	}
+	for _, tt := range tests {
+		if got := tt.mutation.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.mutation, got, tt.want)
+		}
+	}
+}
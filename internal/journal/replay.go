@@ -0,0 +1,129 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"linc/internal/linear"
+)
+
+// Failure is a mutation Linear rejected during replay
+type Failure struct {
+	Mutation Mutation
+	Err      error
+}
+
+// ReplayResult summarises a replay of the journal
+type ReplayResult struct {
+	Applied int
+	// Conflicts were discarded because the issue changed on Linear after the
+	// mutation was queued
+	Conflicts []Mutation
+	Failures  []Failure
+	// Err stops the replay, leaving the remaining mutations queued, e.g.
+	// when Linear is still unreachable
+	Err error
+}
+
+// Replay applies the pending mutations in order. Issue updates are checked
+// against the issue's current updatedAt first; comments never conflict.
+func (j *Journal) Replay(ctx context.Context, client *linear.Client) ReplayResult {
+	var result ReplayResult
+
+	// updatedAt of each issue before any replayed change touched it
+	remoteUpdatedAt := make(map[string]string)
+
+	for _, m := range j.Pending() {
+		if m.Kind != KindComment && m.BaseUpdatedAt != "" {
+			remote, ok := remoteUpdatedAt[m.IssueID]
+			if !ok {
+				var err error
+				remote, err = client.GetIssueUpdatedAt(ctx, m.IssueID)
+				if err != nil {
+					if stop(err) {
+						result.Err = err
+						return result
+					}
+					result.Failures = append(result.Failures, Failure{Mutation: m, Err: err})
+					if err := j.Remove(m.ID); err != nil {
+						result.Err = err
+						return result
+					}
+					continue
+				}
+				remoteUpdatedAt[m.IssueID] = remote
+			}
+
+			if isNewer(remote, m.BaseUpdatedAt) {
+				result.Conflicts = append(result.Conflicts, m)
+				if err := j.Remove(m.ID); err != nil {
+					result.Err = err
+					return result
+				}
+				continue
+			}
+		}
+
+		if err := apply(ctx, client, m); err != nil {
+			if stop(err) {
+				result.Err = err
+				return result
+			}
+			result.Failures = append(result.Failures, Failure{Mutation: m, Err: err})
+		} else {
+			result.Applied++
+		}
+		if err := j.Remove(m.ID); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	return result
+}
+
+func apply(ctx context.Context, client *linear.Client, m Mutation) error {
+	var err error
+	switch m.Kind {
+	case KindTitle:
+		_, err = client.UpdateIssueTitle(ctx, m.IssueID, m.Title)
+	case KindPriority:
+		_, err = client.UpdateIssuePriority(ctx, m.IssueID, m.Priority)
+	case KindState:
+		_, err = client.UpdateIssueState(ctx, m.IssueID, m.StateID)
+	case KindComment:
+		_, err = client.CreateComment(ctx, m.IssueID, m.Body)
+	default:
+		err = fmt.Errorf("unknown mutation kind %q", m.Kind)
+	}
+	return err
+}
+
+// stop reports whether err is transient or affects every mutation, so the
+// replay should end rather than discard the mutation that caused it
+func stop(err error) bool {
+	if linear.IsNetworkError(err) || linear.IsAuthError(err) || linear.IsRateLimited(err) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var apiErr *linear.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 500
+}
+
+// isNewer reports whether timestamp a is after b. Unparseable timestamps are
+// treated as not newer.
+func isNewer(a, b string) bool {
+	ta, err := time.Parse(time.RFC3339, a)
+	if err != nil {
+		return false
+	}
+	tb, err := time.Parse(time.RFC3339, b)
+	if err != nil {
+		return false
+	}
+	return ta.After(tb)
+}
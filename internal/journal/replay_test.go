@@ -0,0 +1,104 @@
+package journal
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"linc/internal/linear"
+	"linc/internal/linear/lineartest"
+)
+
+func newJournal(t *testing.T, mutations ...Mutation) *Journal {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	j, err := Open("ws-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mutations {
+		if err := j.Append(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return j
+}
+
+func TestReplay(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	client := s.Client()
+	ctx := context.Background()
+
+	// An edit made online, whose updatedAt the next offline edit starts from
+	updatedAt, err := client.UpdateIssueTitle(ctx, "issue-1", "Renamed online")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const stale = "2020-01-01T00:00:00.000Z"
+	j := newJournal(t,
+		Mutation{Kind: KindTitle, IssueID: "issue-1", BaseUpdatedAt: updatedAt, Title: "Renamed offline"},
+		Mutation{Kind: KindPriority, IssueID: "issue-1", BaseUpdatedAt: updatedAt, Priority: 1},
+		Mutation{Kind: KindPriority, IssueID: "issue-2", BaseUpdatedAt: stale, Priority: 4},
+		Mutation{Kind: KindComment, IssueID: "issue-2", BaseUpdatedAt: stale, Body: "Queued comment"},
+		Mutation{Kind: KindTitle, IssueID: "issue-missing", BaseUpdatedAt: stale, Title: "Lost"},
+	)
+
+	result := j.Replay(ctx, client)
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	// Changes after our own online edit apply, each checked against the
+	// issue as it was before the replay
+	if result.Applied != 3 {
+		t.Errorf("applied %d mutations, want 3", result.Applied)
+	}
+	// Linear changed issue-2 after the stale change was queued
+	if len(result.Conflicts) != 1 || result.Conflicts[0].IssueID != "issue-2" || result.Conflicts[0].Kind != KindPriority {
+		t.Errorf("conflicts = %+v, want the priority of issue-2", result.Conflicts)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Mutation.IssueID != "issue-missing" {
+		t.Errorf("failures = %+v, want the missing issue's rename", result.Failures)
+	}
+	if j.Len() != 0 {
+		t.Errorf("%d mutations left queued", j.Len())
+	}
+
+	var issue1, issue2 linear.Issue
+	s.Update(func(data *lineartest.Dataset) {
+		issue1, issue2 = *data.Issue("issue-1"), *data.Issue("issue-2")
+	})
+	if issue1.Title != "Renamed offline" || issue1.Priority != 1 {
+		t.Errorf("issue-1 = %q with priority %d, want the offline changes", issue1.Title, issue1.Priority)
+	}
+	if issue2.Priority == 4 {
+		t.Error("the conflicting priority of issue-2 was applied")
+	}
+}
+
+func TestReplayStopsWhileUnreachable(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	client := s.Client(linear.WithMaxRetries(0))
+	ctx := context.Background()
+
+	j := newJournal(t,
+		Mutation{Kind: KindComment, IssueID: "issue-1", Body: "First"},
+		Mutation{Kind: KindComment, IssueID: "issue-1", Body: "Second"},
+	)
+
+	s.FailNext(1, http.StatusServiceUnavailable)
+	result := j.Replay(ctx, client)
+	if result.Err == nil || result.Applied != 0 {
+		t.Fatalf("replay = %+v, want it stopped by the error", result)
+	}
+	if j.Len() != 2 {
+		t.Fatalf("%d mutations left queued, want both", j.Len())
+	}
+
+	result = j.Replay(ctx, client)
+	if result.Err != nil || result.Applied != 2 || j.Len() != 0 {
+		t.Errorf("second replay = %+v with %d left, want both applied", result, j.Len())
+	}
+}
@@ -0,0 +1,96 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operation is one top-level field of a batched query
+type Operation struct {
+	// Alias names the field in the composed query and in the response. The
+	// field's variables are prefixed with it so operations can't collide.
+	Alias string
+	// Field is the selection, e.g. `team(id: $teamId) { name }`
+	Field string
+	// VarTypes declares the GraphQL type of each variable used by Field
+	VarTypes map[string]string
+	Vars     map[string]interface{}
+	// Result receives the field's data
+	Result interface{}
+}
+
+var variablePattern = regexp.MustCompile(`\$(\w+)`)
+
+// ExecuteBatch sends the operations as a single query named name, saving
+// a round trip and API quota compared to separate requests
+func (c *Client) ExecuteBatch(ctx context.Context, name string, ops ...Operation) error {
+	query, vars, err := composeBatch(name, ops)
+	if err != nil {
+		return err
+	}
+
+	var result map[string]json.RawMessage
+	if err := c.execute(ctx, query, vars, &result); err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		data, ok := result[op.Alias]
+		if !ok {
+			return fmt.Errorf("missing %s in batched response", op.Alias)
+		}
+		if err := json.Unmarshal(data, op.Result); err != nil {
+			return fmt.Errorf("failed to unmarshal %s: %w", op.Alias, err)
+		}
+	}
+	return nil
+}
+
+// composeBatch builds the query text and variables for a batch
+func composeBatch(name string, ops []Operation) (string, map[string]interface{}, error) {
+	var defs []string
+	var fields strings.Builder
+	vars := make(map[string]interface{})
+	aliases := make(map[string]bool)
+
+	for _, op := range ops {
+		if aliases[op.Alias] {
+			return "", nil, fmt.Errorf("duplicate alias %q in batch", op.Alias)
+		}
+		aliases[op.Alias] = true
+
+		names := make([]string, 0, len(op.VarTypes))
+		for varName := range op.VarTypes {
+			names = append(names, varName)
+		}
+		sort.Strings(names)
+		for _, varName := range names {
+			defs = append(defs, fmt.Sprintf("$%s_%s: %s", op.Alias, varName, op.VarTypes[varName]))
+			if value, ok := op.Vars[varName]; ok {
+				vars[op.Alias+"_"+varName] = value
+			}
+		}
+
+		var undeclared error
+		field := variablePattern.ReplaceAllStringFunc(op.Field, func(v string) string {
+			if _, ok := op.VarTypes[v[1:]]; !ok && undeclared == nil {
+				undeclared = fmt.Errorf("variable %s of %s has no type", v, op.Alias)
+			}
+			return "$" + op.Alias + "_" + v[1:]
+		})
+		if undeclared != nil {
+			return "", nil, undeclared
+		}
+		fmt.Fprintf(&fields, "  %s: %s\n", op.Alias, strings.TrimSpace(field))
+	}
+
+	query := "query " + name
+	if len(defs) > 0 {
+		query += "(" + strings.Join(defs, ", ") + ")"
+	}
+	return query + " {\n" + fields.String() + "}\n", vars, nil
+}
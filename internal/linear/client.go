@@ -2,24 +2,157 @@ package linear
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
-const apiURL = "https://api.linear.app/graphql"
+// DefaultEndpoint is the Linear GraphQL API endpoint used unless overridden
+const DefaultEndpoint = "https://api.linear.app/graphql"
+
+const (
+	// DefaultPageSize is the number of issues requested per page
+	DefaultPageSize = 50
+	// DefaultMaxIssues caps how many issues are collected across pages
+	DefaultMaxIssues = 500
+)
 
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
+	apiKey      string
+	endpoint    string
+	httpClient  *http.Client
+	pageSize    int
+	maxIssues   int
+	maxRetries  int
+	onRetry     *retryNotifier
+	rateLimiter *rateLimiter
+}
+
+// retryNotifier holds the callback invoked before each retry. It is replaced
+// from main while requests started by the previous UI may still be running.
+type retryNotifier struct {
+	mu sync.Mutex
+	fn func(RetryEvent)
+}
+
+func (n *retryNotifier) set(fn func(RetryEvent)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.fn = fn
+}
+
+func (n *retryNotifier) get() func(RetryEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.fn
+}
+
+// Option configures optional Client settings
+type Option func(*Client)
+
+// WithEndpoint points the client at a different GraphQL endpoint,
+// e.g. a local fake server
+func WithEndpoint(endpoint string) Option {
+	return func(c *Client) {
+		if endpoint != "" {
+			c.endpoint = endpoint
+		}
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithTransport sets the transport of the client's HTTP client
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
+
+// WithTimeout bounds each HTTP request made by the client
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Timeout = timeout
+		c.httpClient = &httpClient
+	}
+}
+
+// WithMaxRetries sets how many times failed requests are retried
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		if n >= 0 {
+			c.maxRetries = n
+		}
+	}
+}
+
+// WithRetryNotifier registers a callback invoked before each retry
+func WithRetryNotifier(fn func(RetryEvent)) Option {
+	return func(c *Client) {
+		c.onRetry.set(fn)
+	}
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{
-		apiKey:     apiKey,
-		httpClient: &http.Client{},
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		apiKey:      apiKey,
+		endpoint:    DefaultEndpoint,
+		httpClient:  &http.Client{Timeout: DefaultTimeout},
+		pageSize:    DefaultPageSize,
+		maxIssues:   DefaultMaxIssues,
+		maxRetries:  DefaultMaxRetries,
+		onRetry:     &retryNotifier{},
+		rateLimiter: &rateLimiter{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithAPIKey returns a copy of the client that authenticates with a different
+// API key but keeps the endpoint, transport and limits
+func (c *Client) WithAPIKey(apiKey string) *Client {
+	clone := *c
+	clone.apiKey = apiKey
+	clone.rateLimiter = &rateLimiter{}
+	return &clone
+}
+
+// SetRetryNotifier replaces the callback invoked before each retry, e.g. to
+// surface retries in whichever UI is currently running. nil disables it.
+// Copies made with WithAPIKey share the callback.
+func (c *Client) SetRetryNotifier(fn func(RetryEvent)) {
+	c.onRetry.set(fn)
+}
+
+// Endpoint returns the GraphQL endpoint the client talks to
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// SetMaxIssues sets the upper bound on issues collected when following
+// pagination. Values <= 0 restore the default.
+func (c *Client) SetMaxIssues(n int) {
+	if n <= 0 {
+		n = DefaultMaxIssues
+	}
+	c.maxIssues = n
 }
 
 type graphQLRequest struct {
@@ -29,12 +162,15 @@ type graphQLRequest struct {
 
 type graphQLResponse struct {
 	Data   json.RawMessage `json:"data"`
-	Errors []struct {
-		Message string `json:"message"`
-	} `json:"errors"`
+	Errors []GraphQLError  `json:"errors"`
 }
 
-func (c *Client) execute(query string, variables map[string]interface{}, result interface{}) error {
+// execute sends a GraphQL request, retrying network errors and 5xx
+// responses with jittered exponential backoff and waiting out rate limits
+// (HTTP 429 or RATELIMITED errors).
+// Mutations are only retried when rate limited, as they may otherwise have
+// been applied already.
+func (c *Client) execute(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
 	reqBody := graphQLRequest{
 		Query:     query,
 		Variables: variables,
@@ -45,9 +181,73 @@ func (c *Client) execute(query string, variables map[string]interface{}, result
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", apiURL, bytes.NewReader(jsonBody))
+	isMutation := strings.HasPrefix(strings.TrimSpace(query), "mutation")
+	maxAttempts := c.maxRetries + 1
+
+	for attempt := 1; ; attempt++ {
+		if wait := c.rateLimiter.delay(); wait > 0 {
+			if wait > maxRateLimitWait {
+				return fmt.Errorf("rate limited by Linear, quota resets in %s", wait.Round(time.Second))
+			}
+			c.notifyRetry(RetryEvent{Attempt: attempt - 1, MaxAttempts: maxAttempts, Wait: wait, RateLimited: true})
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+
+		resp, body, err := c.send(ctx, jsonBody)
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		retryable := err != nil && !isMutation
+		rateLimited := false
+		var wait time.Duration
+		if resp != nil {
+			c.rateLimiter.update(resp.Header)
+			switch {
+			case resp.StatusCode >= 500:
+				retryable = !isMutation
+				err = newAPIError(resp.StatusCode, body)
+			default:
+				err = decodeResponse(resp.StatusCode, body, result)
+				if !IsRateLimited(err) {
+					return err
+				}
+				rateLimited = true
+				retryable = true
+				wait = retryAfter(resp.Header)
+			}
+		}
+
+		if !retryable || attempt >= maxAttempts {
+			return err
+		}
+		if wait == 0 {
+			wait = backoff(attempt)
+		}
+		if wait > maxRateLimitWait {
+			return err
+		}
+
+		c.notifyRetry(RetryEvent{Attempt: attempt, MaxAttempts: maxAttempts, Wait: wait, RateLimited: rateLimited, Err: err})
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) notifyRetry(event RetryEvent) {
+	if fn := c.onRetry.get(); fn != nil {
+		fn(event)
+	}
+}
+
+// send performs a single HTTP attempt and reads the full response body
+func (c *Client) send(ctx context.Context, jsonBody []byte) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(jsonBody))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -55,17 +255,21 @@ func (c *Client) execute(query string, variables map[string]interface{}, result
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return nil, nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	return resp, body, nil
+}
+
+func decodeResponse(statusCode int, body []byte, result interface{}) error {
+	if statusCode != http.StatusOK {
+		return newAPIError(statusCode, body)
 	}
 
 	var gqlResp graphQLResponse
@@ -74,7 +278,7 @@ func (c *Client) execute(query string, variables map[string]interface{}, result
 	}
 
 	if len(gqlResp.Errors) > 0 {
-		return fmt.Errorf("GraphQL error: %s", gqlResp.Errors[0].Message)
+		return &APIError{StatusCode: statusCode, Errors: gqlResp.Errors}
 	}
 
 	if err := json.Unmarshal(gqlResp.Data, result); err != nil {
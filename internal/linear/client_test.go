@@ -0,0 +1,353 @@
+package linear_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"linc/internal/linear"
+	"linc/internal/linear/lineartest"
+)
+
+// addIssues adds n open issues to the team, the even ones assigned to the
+// viewer, all updated at updatedAt
+func addIssues(s *lineartest.Server, n int, updatedAt string) {
+	s.Update(func(data *lineartest.Dataset) {
+		todo := *data.State("state-todo")
+		team := data.Issues[0].Team
+		for i := 0; i < n; i++ {
+			issue := linear.Issue{
+				ID:         fmt.Sprintf("bulk-%d", i),
+				Identifier: "ENG-" + strconv.Itoa(100+i),
+				Title:      fmt.Sprintf("Bulk issue %d", i),
+				UpdatedAt:  updatedAt,
+				State:      todo,
+				Team:       team,
+			}
+			if i%2 == 0 {
+				viewer := data.Viewer
+				issue.Assignee = &viewer
+			}
+			data.Issues = append(data.Issues, issue)
+		}
+	})
+}
+
+func TestGetAllTeamIssuesPage(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	addIssues(s, 100, "2025-03-01T09:00:00.000Z")
+	client := s.Client()
+	ctx := context.Background()
+
+	// 5 open issues from the dataset plus 100 bulk ones, in pages of 50
+	var ids []string
+	after := ""
+	for pages := 1; ; pages++ {
+		page, err := client.GetAllTeamIssuesPage(ctx, "team-eng", after)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, issue := range page.Issues {
+			ids = append(ids, issue.ID)
+		}
+		if !page.PageInfo.HasNextPage {
+			if pages != 3 {
+				t.Errorf("read %d pages, want 3", pages)
+			}
+			break
+		}
+		after = page.PageInfo.EndCursor
+	}
+	if len(ids) != 105 {
+		t.Errorf("read %d issues, want 105", len(ids))
+	}
+	if slices.Contains(ids, "issue-6") {
+		t.Error("completed issue-6 was listed")
+	}
+
+	mine, err := client.GetAssignedIssuesPage(ctx, "team-eng", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 from the dataset plus 50 even bulk ones
+	if len(mine.Issues) != 50 || !mine.PageInfo.HasNextPage {
+		t.Errorf("first page of my issues has %d issues, more: %v, want 50 and more", len(mine.Issues), mine.PageInfo.HasNextPage)
+	}
+	for _, issue := range mine.Issues {
+		if issue.Assignee == nil || issue.Assignee.ID != "user-viewer" {
+			t.Fatalf("%s in my issues is not assigned to the viewer", issue.Identifier)
+		}
+	}
+}
+
+func TestGetTeamSnapshot(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	addIssues(s, 100, "2025-03-01T09:00:00.000Z")
+
+	snapshot, err := s.Client().GetTeamSnapshot(context.Background(), "team-eng")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// States, team issues and my issues arrive in a single request
+	if ops := s.Operations(); !slices.Equal(ops, []string{"TeamSnapshot"}) {
+		t.Fatalf("operations = %v, want one TeamSnapshot", ops)
+	}
+	vars := s.Requests()[0].Variables
+	for _, name := range []string{"team_teamId", "issues_teamId", "issues_first", "mine_teamId", "mine_first"} {
+		if _, ok := vars[name]; !ok {
+			t.Errorf("batched variables %v lack %s", vars, name)
+		}
+	}
+
+	if len(snapshot.States) != 7 || snapshot.States[len(snapshot.States)-1].Type != "canceled" {
+		t.Errorf("states = %v, want 7 ending with the canceled ones", snapshot.States)
+	}
+	if len(snapshot.Issues.Issues) != 50 || !snapshot.Issues.PageInfo.HasNextPage {
+		t.Errorf("team issues: %d, more: %v, want a full first page", len(snapshot.Issues.Issues), snapshot.Issues.PageInfo.HasNextPage)
+	}
+	// My issues are paged separately, so none are missed among other issues
+	if len(snapshot.MyIssues.Issues) != 50 || !snapshot.MyIssues.PageInfo.HasNextPage {
+		t.Errorf("my issues: %d, more: %v, want a full first page", len(snapshot.MyIssues.Issues), snapshot.MyIssues.PageInfo.HasNextPage)
+	}
+}
+
+func TestGetTeamSnapshotFindsMyIssuesBeyondTheFirstPage(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	addIssues(s, 120, "2025-03-01T09:00:00.000Z")
+	// Other issues come first, pushing the viewer's off the team's first page
+	mine := func(issue linear.Issue) bool { return issue.Assignee != nil && issue.Assignee.ID == "user-viewer" }
+	s.Update(func(data *lineartest.Dataset) {
+		slices.SortStableFunc(data.Issues, func(a, b linear.Issue) int {
+			switch {
+			case mine(a) == mine(b):
+				return 0
+			case mine(a):
+				return 1
+			}
+			return -1
+		})
+	})
+
+	snapshot, err := s.Client().GetTeamSnapshot(context.Background(), "team-eng")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := slices.IndexFunc(snapshot.Issues.Issues, mine); i >= 0 {
+		t.Fatalf("%s of the viewer is on the team's first page, the test needs none there", snapshot.Issues.Issues[i].Identifier)
+	}
+	// Yet a full first page of the viewer's issues arrives with it
+	if len(snapshot.MyIssues.Issues) != 50 || !slices.ContainsFunc(snapshot.MyIssues.Issues, func(issue linear.Issue) bool {
+		return issue.Identifier == "ENG-1"
+	}) {
+		t.Errorf("my issues: %d, want a full page including ENG-1", len(snapshot.MyIssues.Issues))
+	}
+	if ops := s.Operations(); len(ops) != 1 {
+		t.Errorf("operations = %v, want one request", ops)
+	}
+}
+
+func TestGetTeamUpdates(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	addIssues(s, 120, "2025-03-01T09:00:00.000Z")
+	s.Update(func(data *lineartest.Dataset) {
+		archived := data.Issue("ENG-1")
+		archived.ArchivedAt = "2025-03-02T09:00:00.000Z"
+		archived.UpdatedAt = archived.ArchivedAt
+	})
+	client := s.Client()
+
+	updates, err := client.GetTeamUpdates(context.Background(), "team-eng", "2025-02-15T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first page is batched with the states, the rest is paged
+	want := []string{"TeamUpdates", "TeamIssuesUpdatedSince", "TeamIssuesUpdatedSince"}
+	if ops := s.Operations(); !slices.Equal(ops, want) {
+		t.Errorf("operations = %v, want %v", ops, want)
+	}
+	if len(updates.Issues) != 121 {
+		t.Errorf("got %d updated issues, want 121", len(updates.Issues))
+	}
+	archived := slices.IndexFunc(updates.Issues, func(issue linear.Issue) bool { return issue.Identifier == "ENG-1" })
+	if archived < 0 || updates.Issues[archived].ArchivedAt == "" {
+		t.Error("archived ENG-1 missing from the updates or not marked archived")
+	}
+
+	// Pagination stops at the issue limit
+	client.SetMaxIssues(60)
+	updates, err = client.GetTeamUpdates(context.Background(), "team-eng", "2025-02-15T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates.Issues) != 60 {
+		t.Errorf("got %d updated issues with a limit of 60", len(updates.Issues))
+	}
+}
+
+func TestExecuteBatchRejectsBadOperations(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	client := s.Client()
+	var result struct{}
+
+	err := client.ExecuteBatch(context.Background(), "Bad",
+		linear.Operation{Alias: "a", Field: "viewer { id }", Result: &result},
+		linear.Operation{Alias: "a", Field: "viewer { id }", Result: &result},
+	)
+	if err == nil {
+		t.Error("duplicate aliases were accepted")
+	}
+
+	err = client.ExecuteBatch(context.Background(), "Bad",
+		linear.Operation{Alias: "a", Field: "team(id: $teamId) { id }", Result: &result},
+	)
+	if err == nil {
+		t.Error("a variable without a type was accepted")
+	}
+	if ops := s.Operations(); len(ops) != 0 {
+		t.Errorf("invalid batches were sent: %v", ops)
+	}
+}
+
+func TestRetriesServerErrors(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+
+	var events []linear.RetryEvent
+	client := s.Client(linear.WithRetryNotifier(func(event linear.RetryEvent) {
+		events = append(events, event)
+	}))
+
+	s.FailNext(1, http.StatusBadGateway)
+	if _, err := client.GetViewer(context.Background()); err != nil {
+		t.Fatalf("query failed despite a retry: %v", err)
+	}
+	if len(s.Operations()) != 2 {
+		t.Errorf("sent %d requests, want 2", len(s.Operations()))
+	}
+	if len(events) != 1 || events[0].Attempt != 1 || events[0].RateLimited {
+		t.Errorf("retry events = %+v, want one for attempt 1", events)
+	}
+
+	// Mutations may have been applied, so they aren't retried
+	s.FailNext(1, http.StatusBadGateway)
+	_, err := client.UpdateIssueTitle(context.Background(), "issue-1", "Renamed")
+	var apiErr *linear.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("mutation error = %v, want a 502 APIError", err)
+	}
+	if len(s.Operations()) != 3 {
+		t.Errorf("sent %d requests, want the mutation sent once", len(s.Operations()))
+	}
+}
+
+func TestGivesUpAfterMaxRetries(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	client := s.Client(linear.WithMaxRetries(1))
+
+	s.FailNext(3, http.StatusServiceUnavailable)
+	_, err := client.GetViewer(context.Background())
+	var apiErr *linear.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("error = %v, want a 503 APIError", err)
+	}
+	if len(s.Operations()) != 2 {
+		t.Errorf("sent %d requests, want 2", len(s.Operations()))
+	}
+}
+
+func TestWaitsOutRateLimits(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+
+	var events []linear.RetryEvent
+	client := s.Client(linear.WithRetryNotifier(func(event linear.RetryEvent) {
+		events = append(events, event)
+	}))
+
+	s.RateLimitNext(1, 200*time.Millisecond)
+	start := time.Now()
+	// Rate limited mutations are retried, as Linear didn't apply them
+	updatedAt, err := client.UpdateIssueTitle(context.Background(), "issue-1", "Renamed")
+	if err != nil {
+		t.Fatalf("mutation failed despite waiting: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("retried after %s, before the quota reset", elapsed)
+	}
+	if len(events) == 0 || !events[0].RateLimited {
+		t.Errorf("retry events = %+v, want a rate limited one", events)
+	}
+
+	var issue linear.Issue
+	s.Update(func(data *lineartest.Dataset) { issue = *data.Issue("issue-1") })
+	if issue.Title != "Renamed" {
+		t.Errorf("title = %q, want Renamed", issue.Title)
+	}
+	if updatedAt == "" || updatedAt != issue.UpdatedAt {
+		t.Errorf("returned updatedAt %q, want the issue's %q", updatedAt, issue.UpdatedAt)
+	}
+}
+
+func TestRateLimitTooLongToWait(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	client := s.Client()
+
+	s.RateLimitNext(1, 10*time.Minute)
+	start := time.Now()
+	_, err := client.GetViewer(context.Background())
+	if !linear.IsRateLimited(err) {
+		t.Errorf("error = %v, want a rate limit error", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("waited for a reset further away than linc waits")
+	}
+}
+
+func TestAPIErrorCodes(t *testing.T) {
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	ctx := context.Background()
+
+	_, err := s.Client().WithAPIKey("lin_api_wrong").GetViewer(ctx)
+	if !linear.IsAuthError(err) || linear.IsForbidden(err) || linear.IsRateLimited(err) {
+		t.Errorf("wrong API key: %v, want only an auth error", err)
+	}
+	var apiErr *linear.APIError
+	if !errors.As(err, &apiErr) || !slices.Equal(apiErr.Codes(), []string{linear.CodeAuthentication}) {
+		t.Errorf("wrong API key: codes of %v, want [%s]", err, linear.CodeAuthentication)
+	}
+
+	_, err = s.Client().UpdateIssueTitle(ctx, "issue-missing", "Renamed")
+	if !errors.As(err, &apiErr) || !apiErr.HasCode(linear.CodeInvalidInput) || apiErr.StatusCode != http.StatusOK {
+		t.Errorf("missing issue: %v, want an INVALID_INPUT error", err)
+	}
+	if got, want := err.Error(), "GraphQL error: Entity not found: Issue"; got != want {
+		t.Errorf("missing issue: message %q, want %q", got, want)
+	}
+	if linear.IsAuthError(err) || linear.IsNetworkError(err) {
+		t.Errorf("missing issue: %v classified as an auth or network error", err)
+	}
+
+	s.FailNext(1, http.StatusForbidden)
+	if _, err := s.Client().GetViewer(ctx); !linear.IsForbidden(err) {
+		t.Errorf("403: %v, want a forbidden error", err)
+	}
+
+	s.Close()
+	if _, err := s.Client(linear.WithMaxRetries(0)).GetViewer(ctx); !linear.IsNetworkError(err) {
+		t.Errorf("closed server: %v, want a network error", err)
+	}
+}
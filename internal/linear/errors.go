@@ -0,0 +1,137 @@
+package linear
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Error codes reported by Linear in GraphQL error extensions
+const (
+	CodeAuthentication = "AUTHENTICATION_ERROR"
+	CodeForbidden      = "FORBIDDEN"
+	CodeRateLimited    = "RATELIMITED"
+	CodeInvalidInput   = "INVALID_INPUT"
+)
+
+// GraphQLError is a single error returned by the Linear API
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Code returns the error's extension code, e.g. AUTHENTICATION_ERROR
+func (e GraphQLError) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// PathString returns the error path in dotted form, e.g. issue.comments.0
+func (e GraphQLError) PathString() string {
+	parts := make([]string, len(e.Path))
+	for i, p := range e.Path {
+		parts[i] = fmt.Sprint(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// APIError is returned when the Linear API responds with a non-200 status
+// or with GraphQL errors. It carries every error the API reported.
+type APIError struct {
+	StatusCode int
+	Errors     []GraphQLError
+	Body       string // raw response body when it contained no GraphQL errors
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+	}
+
+	msgs := make([]string, len(e.Errors))
+	for i, gqlErr := range e.Errors {
+		msgs[i] = gqlErr.Message
+		if path := gqlErr.PathString(); path != "" {
+			msgs[i] += " (at " + path + ")"
+		}
+	}
+	if len(msgs) == 1 {
+		return "GraphQL error: " + msgs[0]
+	}
+	return "GraphQL errors: " + strings.Join(msgs, "; ")
+}
+
+// HasCode reports whether any of the errors carries the given extension code
+func (e *APIError) HasCode(code string) bool {
+	for _, gqlErr := range e.Errors {
+		if gqlErr.Code() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Codes returns the distinct extension codes of the errors
+func (e *APIError) Codes() []string {
+	var codes []string
+	seen := make(map[string]bool)
+	for _, gqlErr := range e.Errors {
+		if code := gqlErr.Code(); code != "" && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// newAPIError builds an APIError from a response, keeping the GraphQL
+// errors when the body contains them
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+	var gqlResp graphQLResponse
+	if err := json.Unmarshal(body, &gqlResp); err == nil && len(gqlResp.Errors) > 0 {
+		apiErr.Errors = gqlResp.Errors
+	} else {
+		apiErr.Body = string(body)
+	}
+	return apiErr
+}
+
+// IsAuthError reports whether err means the API key was missing, invalid,
+// expired or revoked
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusUnauthorized || apiErr.HasCode(CodeAuthentication)
+}
+
+// IsForbidden reports whether err means the API key lacks access to a resource
+func IsForbidden(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusForbidden || apiErr.HasCode(CodeForbidden)
+}
+
+// IsRateLimited reports whether err means Linear's rate limit was exceeded
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.HasCode(CodeRateLimited)
+}
+
+// IsNetworkError reports whether err means Linear could not be reached at
+// all, e.g. because the machine is offline
+func IsNetworkError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
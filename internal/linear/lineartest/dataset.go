@@ -0,0 +1,116 @@
+package lineartest
+
+import (
+	"strconv"
+	"strings"
+
+	"linc/internal/linear"
+)
+
+// Dataset is the in-memory data served by the fake Linear API.
+// Tests may edit it freely through Server.Update.
+type Dataset struct {
+	Viewer       linear.User
+	Organization linear.Organization
+	Teams        []linear.Team
+	States       map[string][]linear.State // team ID -> workflow states
+	Issues       []linear.Issue
+}
+
+// NewDataset returns a small workspace with one team, the default Linear
+// workflow states and a handful of issues, some assigned to the viewer
+func NewDataset() *Dataset {
+	viewer := linear.User{ID: "user-viewer", Name: "Ada Lovelace", DisplayName: "ada", Email: "ada@example.com"}
+	teammate := linear.User{ID: "user-teammate", Name: "Grace Hopper", DisplayName: "grace", Email: "grace@example.com"}
+	team := linear.Team{ID: "team-eng", Name: "Engineering", Key: "ENG"}
+
+	states := []linear.State{
+		{ID: "state-backlog", Name: "Backlog", Color: "#bec2c8", Type: "backlog", Position: 0},
+		{ID: "state-todo", Name: "Todo", Color: "#e2e2e2", Type: "unstarted", Position: 1},
+		{ID: "state-in-progress", Name: "In Progress", Color: "#f2c94c", Type: "started", Position: 2},
+		{ID: "state-in-review", Name: "In Review", Color: "#0f783c", Type: "started", Position: 3},
+		{ID: "state-done", Name: "Done", Color: "#5e6ad2", Type: "completed", Position: 4},
+		{ID: "state-canceled", Name: "Canceled", Color: "#95a2b3", Type: "canceled", Position: 5},
+		{ID: "state-duplicate", Name: "Duplicate", Color: "#95a2b3", Type: "canceled", Position: 6},
+	}
+
+	issue := func(n int, title string, state linear.State, priority int, assignee *linear.User) linear.Issue {
+		identifier := team.Key + "-" + strconv.Itoa(n)
+		return linear.Issue{
+			ID:          "issue-" + strconv.Itoa(n),
+			Identifier:  identifier,
+			Title:       title,
+			Description: "Description of " + title + ".",
+			Priority:    priority,
+			BranchName:  strings.ToLower(identifier) + "-" + slug(title),
+			URL:         "https://linear.app/acme/issue/" + identifier,
+			CreatedAt:   "2025-01-0" + strconv.Itoa(n%9+1) + "T09:00:00.000Z",
+			UpdatedAt:   "2025-02-0" + strconv.Itoa(n%9+1) + "T09:00:00.000Z",
+			State:       state,
+			Assignee:    assignee,
+			Team:        team,
+		}
+	}
+
+	return &Dataset{
+		Viewer:       viewer,
+		Organization: linear.Organization{ID: "org-acme", Name: "Acme"},
+		Teams:        []linear.Team{team},
+		States:       map[string][]linear.State{team.ID: states},
+		Issues: []linear.Issue{
+			issue(1, "Set up CI pipeline", states[1], 2, &viewer),
+			issue(2, "Fix login redirect loop", states[2], 1, &viewer),
+			issue(3, "Add dark mode", states[1], 3, &teammate),
+			issue(4, "Write onboarding docs", states[0], 4, nil),
+			issue(5, "Migrate to Postgres 16", states[3], 2, &viewer),
+			issue(6, "Remove legacy exporter", states[4], 0, &teammate),
+		},
+	}
+}
+
+// Issue returns the issue with the given ID or identifier, or nil
+func (d *Dataset) Issue(idOrIdentifier string) *linear.Issue {
+	for i := range d.Issues {
+		if d.Issues[i].ID == idOrIdentifier || strings.EqualFold(d.Issues[i].Identifier, idOrIdentifier) {
+			return &d.Issues[i]
+		}
+	}
+	return nil
+}
+
+// State returns the workflow state with the given ID, or nil
+func (d *Dataset) State(id string) *linear.State {
+	for _, states := range d.States {
+		for i := range states {
+			if states[i].ID == id {
+				return &states[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Team returns the team with the given ID, or nil
+func (d *Dataset) Team(id string) *linear.Team {
+	for i := range d.Teams {
+		if d.Teams[i].ID == id {
+			return &d.Teams[i]
+		}
+	}
+	return nil
+}
+
+func slug(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
@@ -0,0 +1,469 @@
+// Package lineartest provides an in-process fake of the Linear GraphQL API.
+//
+// The fake understands the queries and mutations sent by linear.Client and
+// serves them from an editable in-memory Dataset, so the client, the TUI and
+// the start-work flow can be exercised without touching real Linear data.
+package lineartest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"linc/internal/linear"
+)
+
+// APIKey is the API key accepted by the fake server
+const APIKey = "lin_api_lineartest"
+
+// Request records a GraphQL operation received by the server
+type Request struct {
+	Operation string
+	Variables map[string]interface{}
+}
+
+// Server is a fake Linear GraphQL API backed by a Dataset
+type Server struct {
+	// URL is the GraphQL endpoint of the running server
+	URL string
+
+	httpServer *httptest.Server
+
+	mu        sync.Mutex
+	data      *Dataset
+	requests  []Request
+	commentID int
+	failures  []failure
+}
+
+// failure is a canned error response returned instead of resolving a request
+type failure struct {
+	status int
+	header http.Header
+}
+
+// NewServer starts a fake Linear API serving the given dataset.
+// A nil dataset is replaced by NewDataset().
+func NewServer(data *Dataset) *Server {
+	s := NewHandler(data)
+	s.httpServer = httptest.NewServer(s)
+	s.URL = s.httpServer.URL + "/graphql"
+	return s
+}
+
+// NewHandler returns a fake Linear API that is not listening anywhere, for
+// mounting on a custom http.Server (e.g. for demos)
+func NewHandler(data *Dataset) *Server {
+	if data == nil {
+		data = NewDataset()
+	}
+	return &Server{data: data}
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	if s.httpServer != nil {
+		s.httpServer.Close()
+	}
+}
+
+// Client returns a linear.Client pointed at the server
+func (s *Server) Client(opts ...linear.Option) *linear.Client {
+	opts = append([]linear.Option{linear.WithEndpoint(s.URL)}, opts...)
+	return linear.NewClient(APIKey, opts...)
+}
+
+// Update edits the dataset while holding the server lock
+func (s *Server) Update(fn func(data *Dataset)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.data)
+}
+
+// FailNext makes the next n requests fail with the given HTTP status
+func (s *Server) FailNext(n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, failure{status: status})
+	}
+}
+
+// RateLimitNext makes the next n requests fail with 429 Too Many Requests,
+// advertising that the quota resets after the given duration
+func (s *Server) RateLimitNext(n int, reset time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		header := http.Header{}
+		header.Set("X-RateLimit-Requests-Remaining", "0")
+		header.Set("X-RateLimit-Requests-Reset", strconv.FormatInt(time.Now().Add(reset).UnixMilli(), 10))
+		s.failures = append(s.failures, failure{status: http.StatusTooManyRequests, header: header})
+	}
+}
+
+// Requests returns the operations received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Operations returns the names of the operations received so far
+func (s *Server) Operations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ops := make([]string, len(s.requests))
+	for i, req := range s.requests {
+		ops[i] = req.Operation
+	}
+	return ops
+}
+
+var operationPattern = regexp.MustCompile(`(?m)^\s*(?:query|mutation)\s+(\w+)`)
+
+type gqlError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// ServeHTTP handles a single GraphQL request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.Header.Get("Authorization") != APIKey {
+		writeErrors(w, http.StatusBadRequest, gqlError{
+			Message:    "Authentication required, not authenticated",
+			Extensions: map[string]interface{}{"code": "AUTHENTICATION_ERROR"},
+		})
+		return
+	}
+
+	var req struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, gqlError{Message: "invalid request body: " + err.Error()})
+		return
+	}
+
+	match := operationPattern.FindStringSubmatch(req.Query)
+	if match == nil {
+		writeErrors(w, http.StatusBadRequest, gqlError{Message: "missing operation name"})
+		return
+	}
+	operation := match[1]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{Operation: operation, Variables: req.Variables})
+
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		s.failures = s.failures[1:]
+		for key, values := range f.header {
+			w.Header()[key] = values
+		}
+		writeErrors(w, f.status, gqlError{Message: http.StatusText(f.status)})
+		return
+	}
+
+	data, err := s.resolve(operation, req.Variables)
+	if err != nil {
+		writeErrors(w, http.StatusOK, gqlError{
+			Message:    err.Error(),
+			Extensions: map[string]interface{}{"code": "INVALID_INPUT"},
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func writeErrors(w http.ResponseWriter, status int, errs ...gqlError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": errs})
+}
+
+// batchPart is one aliased field of a batched query, answered like the
+// single operation it was composed from
+type batchPart struct {
+	alias     string
+	operation string
+}
+
+// batches lists the batched queries sent by the client (see
+// linear.Client.ExecuteBatch)
+var batches = map[string][]batchPart{
+	"TeamSnapshot": {{"team", "TeamStates"}, {"issues", "AllTeamIssues"}, {"mine", "AssignedIssues"}},
+	"TeamUpdates":  {{"team", "TeamStates"}, {"issues", "TeamIssuesUpdatedSince"}},
+}
+
+func (s *Server) resolveBatch(parts []batchPart, vars map[string]interface{}) (interface{}, error) {
+	data := make(map[string]interface{})
+	for _, part := range parts {
+		partVars := make(map[string]interface{})
+		for name, value := range vars {
+			if rest, ok := strings.CutPrefix(name, part.alias+"_"); ok {
+				partVars[rest] = value
+			}
+		}
+
+		result, err := s.resolve(part.operation, partVars)
+		if err != nil {
+			return nil, err
+		}
+		// Single operations select exactly one top-level field
+		for _, field := range result.(map[string]interface{}) {
+			data[part.alias] = field
+		}
+	}
+	return data, nil
+}
+
+func (s *Server) resolve(operation string, vars map[string]interface{}) (interface{}, error) {
+	if parts, ok := batches[operation]; ok {
+		return s.resolveBatch(parts, vars)
+	}
+
+	switch operation {
+	case "Viewer":
+		return s.viewer(), nil
+	case "TeamStates":
+		return s.teamStates(stringVar(vars, "teamId"))
+	case "AssignedIssues":
+		return s.issues(vars, func(issue linear.Issue) bool {
+			return open(issue) && issue.Assignee != nil && issue.Assignee.ID == s.data.Viewer.ID
+		}), nil
+	case "AllTeamIssues":
+		return s.issues(vars, open), nil
+	case "TeamIssuesUpdatedSince":
+		// Asked for with includeArchived
+		since := stringVar(vars, "since")
+		return s.issues(vars, func(issue linear.Issue) bool {
+			return updatedAfter(issue.UpdatedAt, since)
+		}), nil
+	case "IssueUpdatedAt":
+		return s.issueUpdatedAt(stringVar(vars, "issueId"))
+	case "IssueWithContext":
+		return s.issueWithContext(stringVar(vars, "issueId"))
+	case "CreateComment":
+		return s.createComment(stringVar(vars, "issueId"), stringVar(vars, "body"))
+	case "UpdateIssueState", "UpdateIssueTitle", "UpdateIssuePriority":
+		return s.updateIssue(vars)
+	}
+	return nil, fmt.Errorf("unsupported operation %q", operation)
+}
+
+func (s *Server) viewer() interface{} {
+	return map[string]interface{}{
+		"viewer": map[string]interface{}{
+			"id":           s.data.Viewer.ID,
+			"name":         s.data.Viewer.Name,
+			"email":        s.data.Viewer.Email,
+			"organization": s.data.Organization,
+			"teams":        map[string]interface{}{"nodes": s.data.Teams},
+		},
+	}
+}
+
+func (s *Server) teamStates(teamID string) (interface{}, error) {
+	if s.data.Team(teamID) == nil {
+		return nil, fmt.Errorf("Entity not found: Team")
+	}
+	return map[string]interface{}{
+		"team": map[string]interface{}{
+			"states": map[string]interface{}{"nodes": s.data.States[teamID]},
+		},
+	}, nil
+}
+
+func (s *Server) issues(vars map[string]interface{}, include func(linear.Issue) bool) interface{} {
+	teamID := stringVar(vars, "teamId")
+
+	var matching []linear.Issue
+	for _, issue := range s.data.Issues {
+		if issue.Team.ID == teamID && include(issue) {
+			matching = append(matching, issue)
+		}
+	}
+
+	start := 0
+	if after := stringVar(vars, "after"); after != "" {
+		if n, err := strconv.Atoi(after); err == nil {
+			start = min(n, len(matching))
+		}
+	}
+	end := len(matching)
+	if first, ok := vars["first"].(float64); ok && first > 0 {
+		end = min(start+int(first), len(matching))
+	}
+
+	nodes := make([]interface{}, 0, end-start)
+	for _, issue := range matching[start:end] {
+		nodes = append(nodes, issueNode(issue))
+	}
+
+	return map[string]interface{}{
+		"issues": map[string]interface{}{
+			"nodes": nodes,
+			"pageInfo": map[string]interface{}{
+				"hasNextPage": end < len(matching),
+				"endCursor":   strconv.Itoa(end),
+			},
+		},
+	}
+}
+
+func (s *Server) issueUpdatedAt(id string) (interface{}, error) {
+	issue := s.data.Issue(id)
+	if issue == nil {
+		return nil, fmt.Errorf("Entity not found: Issue")
+	}
+	return map[string]interface{}{
+		"issue": map[string]interface{}{"id": issue.ID, "updatedAt": issue.UpdatedAt},
+	}, nil
+}
+
+func (s *Server) issueWithContext(id string) (interface{}, error) {
+	issue := s.data.Issue(id)
+	if issue == nil {
+		return nil, fmt.Errorf("Entity not found: Issue")
+	}
+
+	node := issueNode(*issue)
+	node["comments"] = map[string]interface{}{"nodes": nonNil(issue.Comments)}
+	node["attachments"] = map[string]interface{}{"nodes": nonNil(issue.Attachments)}
+	return map[string]interface{}{"issue": node}, nil
+}
+
+func (s *Server) createComment(issueID, body string) (interface{}, error) {
+	issue := s.data.Issue(issueID)
+	if issue == nil {
+		return nil, fmt.Errorf("Entity not found: Issue")
+	}
+
+	s.commentID++
+	comment := linear.Comment{
+		ID:        "comment-" + strconv.Itoa(s.commentID),
+		Body:      body,
+		CreatedAt: now(),
+		User:      s.data.Viewer,
+	}
+	issue.Comments = append(issue.Comments, comment)
+	issue.UpdatedAt = comment.CreatedAt
+
+	return map[string]interface{}{
+		"commentCreate": map[string]interface{}{
+			"success": true,
+			"comment": comment,
+		},
+	}, nil
+}
+
+func (s *Server) updateIssue(vars map[string]interface{}) (interface{}, error) {
+	issue := s.data.Issue(stringVar(vars, "issueId"))
+	if issue == nil {
+		return nil, fmt.Errorf("Entity not found: Issue")
+	}
+
+	if stateID, ok := vars["stateId"].(string); ok {
+		state := s.data.State(stateID)
+		if state == nil {
+			return nil, fmt.Errorf("Entity not found: WorkflowState")
+		}
+		issue.State = *state
+	}
+	if title, ok := vars["title"].(string); ok {
+		issue.Title = title
+	}
+	if priority, ok := vars["priority"].(float64); ok {
+		issue.Priority = int(priority)
+	}
+	issue.UpdatedAt = now()
+
+	return map[string]interface{}{
+		"issueUpdate": map[string]interface{}{
+			"success": true,
+			"issue":   issueNode(*issue),
+		},
+	}, nil
+}
+
+// issueNode renders an issue in the shape of the Linear API, with
+// connections wrapped in nodes
+func issueNode(issue linear.Issue) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          issue.ID,
+		"identifier":  issue.Identifier,
+		"title":       issue.Title,
+		"description": issue.Description,
+		"priority":    issue.Priority,
+		"estimate":    issue.Estimate,
+		"branchName":  issue.BranchName,
+		"url":         issue.URL,
+		"createdAt":   issue.CreatedAt,
+		"updatedAt":   issue.UpdatedAt,
+		"archivedAt":  nullable(issue.ArchivedAt),
+		"state":       issue.State,
+		"assignee":    issue.Assignee,
+		"labels":      map[string]interface{}{"nodes": nonNil(issue.Labels)},
+		"cycle":       issue.Cycle,
+		"team":        issue.Team,
+	}
+}
+
+// open reports whether an issue shows up in the open issue lists, which
+// leave out archived issues like Linear does by default
+func open(issue linear.Issue) bool {
+	return issue.State.Type != "completed" && issue.ArchivedAt == ""
+}
+
+// nullable returns nil for an empty string, which Linear sends as null
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// now returns the current time in the format Linear uses for timestamps
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// updatedAfter reports whether the timestamp is after since; an unparseable
+// since matches everything
+func updatedAfter(updatedAt, since string) bool {
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return true
+	}
+	updatedTime, err := time.Parse(time.RFC3339, updatedAt)
+	return err == nil && updatedTime.After(sinceTime)
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func stringVar(vars map[string]interface{}, name string) string {
+	v, _ := vars[name].(string)
+	return v
+}
@@ -1,5 +1,10 @@
 package linear
 
+import (
+	"context"
+	"strings"
+)
+
 const createCommentMutation = `
 mutation CreateComment($issueId: String!, $body: String!) {
   commentCreate(input: { issueId: $issueId, body: $body }) {
@@ -28,6 +33,7 @@ mutation UpdateIssueState($issueId: String!, $stateId: String!) {
         id
         name
       }
+      updatedAt
     }
   }
 }
@@ -40,6 +46,7 @@ mutation UpdateIssueTitle($issueId: String!, $title: String!) {
     issue {
       id
       title
+      updatedAt
     }
   }
 }
@@ -52,12 +59,13 @@ mutation UpdateIssuePriority($issueId: String!, $priority: Int!) {
     issue {
       id
       priority
+      updatedAt
     }
   }
 }
 `
 
-func (c *Client) CreateComment(issueID, body string) (*Comment, error) {
+func (c *Client) CreateComment(ctx context.Context, issueID, body string) (*Comment, error) {
 	var result CreateCommentResponse
 
 	vars := map[string]interface{}{
@@ -65,7 +73,7 @@ func (c *Client) CreateComment(issueID, body string) (*Comment, error) {
 		"body":    body,
 	}
 
-	if err := c.execute(createCommentMutation, vars, &result); err != nil {
+	if err := c.execute(ctx, createCommentMutation, vars, &result); err != nil {
 		return nil, err
 	}
 
@@ -76,77 +84,89 @@ func (c *Client) CreateComment(issueID, body string) (*Comment, error) {
 	return &result.CommentCreate.Comment, nil
 }
 
-func (c *Client) UpdateIssueState(issueID, stateID string) error {
-	var result struct {
-		IssueUpdate struct {
-			Success bool `json:"success"`
-		} `json:"issueUpdate"`
-	}
-
-	vars := map[string]interface{}{
+// UpdateIssueState moves an issue to a workflow state and returns the
+// issue's updatedAt after the change
+func (c *Client) UpdateIssueState(ctx context.Context, issueID, stateID string) (string, error) {
+	return c.updateIssue(ctx, updateIssueStateMutation, map[string]interface{}{
 		"issueId": issueID,
 		"stateId": stateID,
-	}
+	})
+}
 
-	if err := c.execute(updateIssueStateMutation, vars, &result); err != nil {
-		return err
-	}
+// UpdateIssueTitle renames an issue and returns the issue's updatedAt after
+// the change
+func (c *Client) UpdateIssueTitle(ctx context.Context, issueID, title string) (string, error) {
+	return c.updateIssue(ctx, updateIssueTitleMutation, map[string]interface{}{
+		"issueId": issueID,
+		"title":   title,
+	})
+}
 
-	return nil
+// UpdateIssuePriority sets an issue's priority and returns the issue's
+// updatedAt after the change
+func (c *Client) UpdateIssuePriority(ctx context.Context, issueID string, priority int) (string, error) {
+	return c.updateIssue(ctx, updateIssuePriorityMutation, map[string]interface{}{
+		"issueId":  issueID,
+		"priority": priority,
+	})
 }
 
-func (c *Client) UpdateIssueTitle(issueID, title string) error {
+// updateIssue runs an issueUpdate mutation and returns the updatedAt it
+// reports, so later changes made offline are based on the issue as changed
+func (c *Client) updateIssue(ctx context.Context, mutation string, vars map[string]interface{}) (string, error) {
 	var result struct {
 		IssueUpdate struct {
 			Success bool `json:"success"`
+			Issue   struct {
+				UpdatedAt string `json:"updatedAt"`
+			} `json:"issue"`
 		} `json:"issueUpdate"`
 	}
 
-	vars := map[string]interface{}{
-		"issueId": issueID,
-		"title":   title,
-	}
-
-	if err := c.execute(updateIssueTitleMutation, vars, &result); err != nil {
-		return err
+	if err := c.execute(ctx, mutation, vars, &result); err != nil {
+		return "", err
 	}
 
-	return nil
+	return result.IssueUpdate.Issue.UpdatedAt, nil
 }
 
-func (c *Client) UpdateIssuePriority(issueID string, priority int) error {
-	var result struct {
-		IssueUpdate struct {
-			Success bool `json:"success"`
-		} `json:"issueUpdate"`
+func (c *Client) GetInProgressStateID(ctx context.Context, teamID string) (string, error) {
+	states, err := c.GetTeamStates(ctx, teamID)
+	if err != nil {
+		return "", err
 	}
 
-	vars := map[string]interface{}{
-		"issueId":  issueID,
-		"priority": priority,
+	for _, state := range states {
+		if state.Type == "started" {
+			return state.ID, nil
+		}
 	}
 
-	if err := c.execute(updateIssuePriorityMutation, vars, &result); err != nil {
-		return err
+	for _, state := range states {
+		if state.Name == "In Progress" {
+			return state.ID, nil
+		}
 	}
 
-	return nil
+	return "", nil
 }
 
-func (c *Client) GetInProgressStateID(teamID string) (string, error) {
-	states, err := c.GetTeamStates(teamID)
+// GetInReviewStateID returns the team's "In Review" state, or empty string if
+// the team's workflow has no review state
+func (c *Client) GetInReviewStateID(ctx context.Context, teamID string) (string, error) {
+	states, err := c.getAllTeamStates(ctx, teamID)
 	if err != nil {
 		return "", err
 	}
 
 	for _, state := range states {
-		if state.Type == "started" {
+		if state.Name == "In Review" {
 			return state.ID, nil
 		}
 	}
 
 	for _, state := range states {
-		if state.Name == "In Progress" {
+		if state.Type == "started" && strings.Contains(strings.ToLower(state.Name), "review") {
 			return state.ID, nil
 		}
 	}
@@ -154,8 +174,8 @@ func (c *Client) GetInProgressStateID(teamID string) (string, error) {
 	return "", nil
 }
 
-func (c *Client) GetCanceledStateID(teamID string) (string, error) {
-	states, err := c.getAllTeamStates(teamID)
+func (c *Client) GetCanceledStateID(ctx context.Context, teamID string) (string, error) {
+	states, err := c.getAllTeamStates(ctx, teamID)
 	if err != nil {
 		return "", err
 	}
@@ -175,8 +195,8 @@ func (c *Client) GetCanceledStateID(teamID string) (string, error) {
 	return "", nil
 }
 
-func (c *Client) GetDuplicateStateID(teamID string) (string, error) {
-	states, err := c.getAllTeamStates(teamID)
+func (c *Client) GetDuplicateStateID(ctx context.Context, teamID string) (string, error) {
+	states, err := c.getAllTeamStates(ctx, teamID)
 	if err != nil {
 		return "", err
 	}
@@ -190,7 +210,7 @@ func (c *Client) GetDuplicateStateID(teamID string) (string, error) {
 	return "", nil
 }
 
-func (c *Client) getAllTeamStates(teamID string) ([]State, error) {
+func (c *Client) getAllTeamStates(ctx context.Context, teamID string) ([]State, error) {
 	var result struct {
 		Team struct {
 			States struct {
@@ -206,7 +226,7 @@ func (c *Client) getAllTeamStates(teamID string) ([]State, error) {
 	}
 
 	vars := map[string]interface{}{"teamId": teamID}
-	if err := c.execute(teamStatesQuery, vars, &result); err != nil {
+	if err := c.execute(ctx, teamStatesQuery, vars, &result); err != nil {
 		return nil, err
 	}
 
@@ -1,5 +1,7 @@
 package linear
 
+import "context"
+
 const viewerQuery = `
 query Viewer {
   viewer {
@@ -21,125 +23,225 @@ query Viewer {
 }
 `
 
+// The *Field constants are top-level selections shared by the named queries
+// below and by batched queries (see ExecuteBatch)
+
+const teamStatesField = `
+team(id: $teamId) {
+  states {
+    nodes {
+      id
+      name
+      color
+      type
+      position
+    }
+  }
+}
+`
+
 const teamStatesQuery = `
 query TeamStates($teamId: String!) {
-  team(id: $teamId) {
-    states {
+` + teamStatesField + `}
+`
+
+const assignedIssuesField = `
+issues(
+  filter: {
+    team: { id: { eq: $teamId } }
+    assignee: { isMe: { eq: true } }
+    state: { type: { nin: ["completed"] } }
+  }
+  orderBy: updatedAt
+  first: $first
+  after: $after
+) {
+  nodes {
+    id
+    identifier
+    title
+    description
+    priority
+    estimate
+    branchName
+    url
+    createdAt
+    updatedAt
+    state {
+      id
+      name
+      color
+      type
+    }
+    assignee {
+      id
+      name
+      displayName
+      email
+    }
+    labels {
       nodes {
         id
         name
         color
-        type
-        position
       }
     }
+    cycle {
+      id
+      number
+      name
+    }
+    team {
+      id
+      name
+      key
+    }
+  }
+  pageInfo {
+    hasNextPage
+    endCursor
   }
 }
 `
 
 const assignedIssuesQuery = `
-query AssignedIssues($teamId: ID!) {
-  issues(
-    filter: {
-      team: { id: { eq: $teamId } }
-      assignee: { isMe: { eq: true } }
-      state: { type: { nin: ["completed"] } }
+query AssignedIssues($teamId: ID!, $first: Int!, $after: String) {
+` + assignedIssuesField + `}
+`
+
+const allTeamIssuesField = `
+issues(
+  filter: {
+    team: { id: { eq: $teamId } }
+    state: { type: { nin: ["completed"] } }
+  }
+  orderBy: updatedAt
+  first: $first
+  after: $after
+) {
+  nodes {
+    id
+    identifier
+    title
+    description
+    priority
+    estimate
+    branchName
+    url
+    createdAt
+    updatedAt
+    state {
+      id
+      name
+      color
+      type
     }
-    orderBy: updatedAt
-    first: 50
-  ) {
-    nodes {
+    assignee {
       id
-      identifier
-      title
-      description
-      priority
-      estimate
-      branchName
-      url
-      createdAt
-      state {
+      name
+      displayName
+      email
+    }
+    labels {
+      nodes {
         id
         name
         color
-        type
-      }
-      assignee {
-        id
-        name
-        email
-      }
-      labels {
-        nodes {
-          id
-          name
-          color
-        }
-      }
-      cycle {
-        id
-        number
-        name
-      }
-      team {
-        id
-        name
-        key
       }
     }
+    cycle {
+      id
+      number
+      name
+    }
+    team {
+      id
+      name
+      key
+    }
+  }
+  pageInfo {
+    hasNextPage
+    endCursor
   }
 }
 `
 
 const allTeamIssuesQuery = `
-query AllTeamIssues($teamId: ID!) {
-  issues(
-    filter: {
-      team: { id: { eq: $teamId } }
-      state: { type: { nin: ["completed"] } }
+query AllTeamIssues($teamId: ID!, $first: Int!, $after: String) {
+` + allTeamIssuesField + `}
+`
+
+const teamIssuesUpdatedSinceField = `
+issues(
+  filter: {
+    team: { id: { eq: $teamId } }
+    updatedAt: { gt: $since }
+  }
+  orderBy: updatedAt
+  includeArchived: true
+  first: $first
+  after: $after
+) {
+  nodes {
+    id
+    identifier
+    title
+    description
+    priority
+    estimate
+    branchName
+    url
+    createdAt
+    updatedAt
+    archivedAt
+    state {
+      id
+      name
+      color
+      type
     }
-    orderBy: updatedAt
-    first: 100
-  ) {
-    nodes {
+    assignee {
       id
-      identifier
-      title
-      description
-      priority
-      estimate
-      branchName
-      url
-      createdAt
-      state {
+      name
+      displayName
+      email
+    }
+    labels {
+      nodes {
         id
         name
         color
-        type
-      }
-      assignee {
-        id
-        name
-        email
-      }
-      labels {
-        nodes {
-          id
-          name
-          color
-        }
-      }
-      cycle {
-        id
-        number
-        name
-      }
-      team {
-        id
-        name
-        key
       }
     }
+    cycle {
+      id
+      number
+      name
+    }
+    team {
+      id
+      name
+      key
+    }
+  }
+  pageInfo {
+    hasNextPage
+    endCursor
+  }
+}
+`
+
+const teamIssuesUpdatedSinceQuery = `
+query TeamIssuesUpdatedSince($teamId: ID!, $since: DateTimeOrDuration!, $first: Int!, $after: String) {
+` + teamIssuesUpdatedSinceField + `}
+`
+
+const issueUpdatedAtQuery = `
+query IssueUpdatedAt($issueId: String!) {
+  issue(id: $issueId) {
+    id
+    updatedAt
   }
 }
 `
@@ -154,6 +256,7 @@ query IssueWithContext($issueId: String!) {
     priority
     branchName
     url
+    updatedAt
     state {
       id
       name
@@ -163,6 +266,7 @@ query IssueWithContext($issueId: String!) {
     assignee {
       id
       name
+      displayName
       email
     }
     labels {
@@ -204,61 +308,57 @@ query IssueWithContext($issueId: String!) {
 }
 `
 
-func (c *Client) GetViewer() (*ViewerResponse, error) {
+func (c *Client) GetViewer(ctx context.Context) (*ViewerResponse, error) {
 	var result ViewerResponse
-	if err := c.execute(viewerQuery, nil, &result); err != nil {
+	if err := c.execute(ctx, viewerQuery, nil, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-func (c *Client) GetWorkspaceInfo() (id, name string, err error) {
-	viewer, err := c.GetViewer()
+func (c *Client) GetWorkspaceInfo(ctx context.Context) (id, name string, err error) {
+	viewer, err := c.GetViewer(ctx)
 	if err != nil {
 		return "", "", err
 	}
 	return viewer.Viewer.Organization.ID, viewer.Viewer.Organization.Name, nil
 }
 
-func FetchWorkspaceInfo(apiKey string) (id, name string, err error) {
-	client := NewClient(apiKey)
-	return client.GetWorkspaceInfo()
+func FetchWorkspaceInfo(ctx context.Context, apiKey string, opts ...Option) (id, name string, err error) {
+	client := NewClient(apiKey, opts...)
+	return client.GetWorkspaceInfo(ctx)
 }
 
-func (c *Client) GetTeamStates(teamID string) ([]State, error) {
+// teamStatesResult is the data selected by teamStatesField
+type teamStatesResult struct {
+	States struct {
+		Nodes []State `json:"nodes"`
+	} `json:"states"`
+}
+
+func (c *Client) GetTeamStates(ctx context.Context, teamID string) ([]State, error) {
 	var result struct {
-		Team struct {
-			States struct {
-				Nodes []struct {
-					ID       string  `json:"id"`
-					Name     string  `json:"name"`
-					Color    string  `json:"color"`
-					Type     string  `json:"type"`
-					Position float64 `json:"position"`
-				} `json:"nodes"`
-			} `json:"states"`
-		} `json:"team"`
+		Team teamStatesResult `json:"team"`
 	}
 
 	vars := map[string]interface{}{"teamId": teamID}
-	if err := c.execute(teamStatesQuery, vars, &result); err != nil {
+	if err := c.execute(ctx, teamStatesQuery, vars, &result); err != nil {
 		return nil, err
 	}
 
+	return orderStates(result.Team.States.Nodes), nil
+}
+
+// orderStates sorts states by position, with completed and then canceled
+// states last
+func orderStates(states []State) []State {
 	var activeStates []State
 	var completedStates []State
 	var canceledStates []State
-	for _, node := range result.Team.States.Nodes {
-		state := State{
-			ID:       node.ID,
-			Name:     node.Name,
-			Color:    node.Color,
-			Type:     node.Type,
-			Position: node.Position,
-		}
-		if node.Type == "canceled" {
+	for _, state := range states {
+		if state.Type == "canceled" {
 			canceledStates = append(canceledStates, state)
-		} else if node.Type == "completed" {
+		} else if state.Type == "completed" {
 			completedStates = append(completedStates, state)
 		} else {
 			activeStates = append(activeStates, state)
@@ -278,125 +378,234 @@ func (c *Client) GetTeamStates(teamID string) ([]State, error) {
 	sort(completedStates)
 	sort(canceledStates)
 
-	return append(append(activeStates, completedStates...), canceledStates...), nil
+	return append(append(activeStates, completedStates...), canceledStates...)
 }
 
-func (c *Client) GetAssignedIssues(teamID string) ([]Issue, error) {
-	var result struct {
-		Issues struct {
-			Nodes []struct {
-				ID          string   `json:"id"`
-				Identifier  string   `json:"identifier"`
-				Title       string   `json:"title"`
-				Description string   `json:"description"`
-				Priority    int      `json:"priority"`
-				Estimate    *float64 `json:"estimate"`
-				BranchName  string   `json:"branchName"`
-				URL         string   `json:"url"`
-				CreatedAt   string   `json:"createdAt"`
-				State       State    `json:"state"`
-				Assignee    *User    `json:"assignee"`
-				Labels      struct {
-					Nodes []Label `json:"nodes"`
-				} `json:"labels"`
-				Cycle *Cycle `json:"cycle"`
-				Team  Team   `json:"team"`
-			} `json:"nodes"`
-		} `json:"issues"`
+// issueNode mirrors the shape of an issue in the list queries, where labels
+// are wrapped in a connection
+type issueNode struct {
+	ID          string   `json:"id"`
+	Identifier  string   `json:"identifier"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Priority    int      `json:"priority"`
+	Estimate    *float64 `json:"estimate"`
+	BranchName  string   `json:"branchName"`
+	URL         string   `json:"url"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+	ArchivedAt  string   `json:"archivedAt"`
+	State       State    `json:"state"`
+	Assignee    *User    `json:"assignee"`
+	Labels      struct {
+		Nodes []Label `json:"nodes"`
+	} `json:"labels"`
+	Cycle *Cycle `json:"cycle"`
+	Team  Team   `json:"team"`
+}
+
+func (n issueNode) toIssue() Issue {
+	return Issue{
+		ID:          n.ID,
+		Identifier:  n.Identifier,
+		Title:       n.Title,
+		Description: n.Description,
+		Priority:    n.Priority,
+		Estimate:    n.Estimate,
+		BranchName:  n.BranchName,
+		URL:         n.URL,
+		CreatedAt:   n.CreatedAt,
+		UpdatedAt:   n.UpdatedAt,
+		ArchivedAt:  n.ArchivedAt,
+		State:       n.State,
+		Assignee:    n.Assignee,
+		Labels:      n.Labels.Nodes,
+		Cycle:       n.Cycle,
+		Team:        n.Team,
 	}
+}
 
-	vars := map[string]interface{}{"teamId": teamID}
-	if err := c.execute(assignedIssuesQuery, vars, &result); err != nil {
+// issueConnection is a page of issues as selected by the list queries
+type issueConnection struct {
+	Nodes    []issueNode `json:"nodes"`
+	PageInfo PageInfo    `json:"pageInfo"`
+}
+
+func (c issueConnection) toPage() *IssuePage {
+	issues := make([]Issue, len(c.Nodes))
+	for i, node := range c.Nodes {
+		issues[i] = node.toIssue()
+	}
+	return &IssuePage{Issues: issues, PageInfo: c.PageInfo}
+}
+
+// GetTeamSnapshot returns the team's workflow states and the first pages of
+// its open issues and of the viewer's, in a single request. The viewer's
+// issues aren't filtered out of the team's page: it holds only the first
+// pageSize issues, so in a larger team most of the viewer's would be missing
+// until every page had been loaded.
+func (c *Client) GetTeamSnapshot(ctx context.Context, teamID string) (*TeamSnapshot, error) {
+	var states teamStatesResult
+	var issues, mine issueConnection
+	err := c.ExecuteBatch(ctx, "TeamSnapshot",
+		Operation{
+			Alias:    "team",
+			Field:    teamStatesField,
+			VarTypes: map[string]string{"teamId": "String!"},
+			Vars:     map[string]interface{}{"teamId": teamID},
+			Result:   &states,
+		},
+		Operation{
+			Alias:    "issues",
+			Field:    allTeamIssuesField,
+			VarTypes: map[string]string{"teamId": "ID!", "first": "Int!", "after": "String"},
+			Vars:     map[string]interface{}{"teamId": teamID, "first": c.pageSize},
+			Result:   &issues,
+		},
+		Operation{
+			Alias:    "mine",
+			Field:    assignedIssuesField,
+			VarTypes: map[string]string{"teamId": "ID!", "first": "Int!", "after": "String"},
+			Vars:     map[string]interface{}{"teamId": teamID, "first": c.pageSize},
+			Result:   &mine,
+		},
+	)
+	if err != nil {
 		return nil, err
 	}
 
-	issues := make([]Issue, len(result.Issues.Nodes))
-	for i, node := range result.Issues.Nodes {
-		issues[i] = Issue{
-			ID:          node.ID,
-			Identifier:  node.Identifier,
-			Title:       node.Title,
-			Description: node.Description,
-			Priority:    node.Priority,
-			Estimate:    node.Estimate,
-			BranchName:  node.BranchName,
-			URL:         node.URL,
-			CreatedAt:   node.CreatedAt,
-			State:       node.State,
-			Assignee:    node.Assignee,
-			Labels:      node.Labels.Nodes,
-			Cycle:       node.Cycle,
-			Team:        node.Team,
+	return &TeamSnapshot{
+		States:   orderStates(states.States.Nodes),
+		Issues:   *issues.toPage(),
+		MyIssues: *mine.toPage(),
+	}, nil
+}
+
+// GetTeamUpdates returns the team's workflow states and every issue updated
+// after the given RFC 3339 timestamp, archived and deleted ones included,
+// batching the states with the first page of issues
+func (c *Client) GetTeamUpdates(ctx context.Context, teamID, since string) (*TeamUpdates, error) {
+	filterVars := map[string]interface{}{"teamId": teamID, "since": since}
+
+	var states teamStatesResult
+	var issues issueConnection
+	err := c.ExecuteBatch(ctx, "TeamUpdates",
+		Operation{
+			Alias:    "team",
+			Field:    teamStatesField,
+			VarTypes: map[string]string{"teamId": "String!"},
+			Vars:     map[string]interface{}{"teamId": teamID},
+			Result:   &states,
+		},
+		Operation{
+			Alias:    "issues",
+			Field:    teamIssuesUpdatedSinceField,
+			VarTypes: map[string]string{"teamId": "ID!", "since": "DateTimeOrDuration!", "first": "Int!", "after": "String"},
+			Vars:     map[string]interface{}{"teamId": teamID, "since": since, "first": c.pageSize},
+			Result:   &issues,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	page := issues.toPage()
+	updated := page.Issues
+	if page.PageInfo.HasNextPage && page.PageInfo.EndCursor != "" && len(updated) < c.maxIssues {
+		more, err := c.collectIssues(ctx, teamIssuesUpdatedSinceQuery, filterVars, page.PageInfo.EndCursor)
+		if err != nil {
+			return nil, err
+		}
+		updated = append(updated, more...)
+		if len(updated) > c.maxIssues {
+			updated = updated[:c.maxIssues]
 		}
 	}
 
-	return issues, nil
+	return &TeamUpdates{States: orderStates(states.States.Nodes), Issues: updated}, nil
+}
+
+// GetAssignedIssuesPage returns a single page of the viewer's open issues,
+// starting after the given cursor (empty for the first page)
+func (c *Client) GetAssignedIssuesPage(ctx context.Context, teamID, after string) (*IssuePage, error) {
+	return c.fetchIssuePage(ctx, assignedIssuesQuery, map[string]interface{}{"teamId": teamID}, after)
+}
+
+// GetAllTeamIssuesPage returns a single page of all open issues in the team,
+// starting after the given cursor (empty for the first page)
+func (c *Client) GetAllTeamIssuesPage(ctx context.Context, teamID, after string) (*IssuePage, error) {
+	return c.fetchIssuePage(ctx, allTeamIssuesQuery, map[string]interface{}{"teamId": teamID}, after)
 }
 
-func (c *Client) GetAllTeamIssues(teamID string) ([]Issue, error) {
+func (c *Client) fetchIssuePage(ctx context.Context, query string, filterVars map[string]interface{}, after string) (*IssuePage, error) {
 	var result struct {
-		Issues struct {
-			Nodes []struct {
-				ID          string   `json:"id"`
-				Identifier  string   `json:"identifier"`
-				Title       string   `json:"title"`
-				Description string   `json:"description"`
-				Priority    int      `json:"priority"`
-				Estimate    *float64 `json:"estimate"`
-				BranchName  string   `json:"branchName"`
-				URL         string   `json:"url"`
-				CreatedAt   string   `json:"createdAt"`
-				State       State    `json:"state"`
-				Assignee    *User    `json:"assignee"`
-				Labels      struct {
-					Nodes []Label `json:"nodes"`
-				} `json:"labels"`
-				Cycle *Cycle `json:"cycle"`
-				Team  Team   `json:"team"`
-			} `json:"nodes"`
-		} `json:"issues"`
+		Issues issueConnection `json:"issues"`
 	}
 
-	vars := map[string]interface{}{"teamId": teamID}
-	if err := c.execute(allTeamIssuesQuery, vars, &result); err != nil {
+	vars := map[string]interface{}{"first": c.pageSize}
+	for k, v := range filterVars {
+		vars[k] = v
+	}
+	if after != "" {
+		vars["after"] = after
+	}
+	if err := c.execute(ctx, query, vars, &result); err != nil {
 		return nil, err
 	}
 
-	issues := make([]Issue, len(result.Issues.Nodes))
-	for i, node := range result.Issues.Nodes {
-		issues[i] = Issue{
-			ID:          node.ID,
-			Identifier:  node.Identifier,
-			Title:       node.Title,
-			Description: node.Description,
-			Priority:    node.Priority,
-			Estimate:    node.Estimate,
-			BranchName:  node.BranchName,
-			URL:         node.URL,
-			CreatedAt:   node.CreatedAt,
-			State:       node.State,
-			Assignee:    node.Assignee,
-			Labels:      node.Labels.Nodes,
-			Cycle:       node.Cycle,
-			Team:        node.Team,
+	return result.Issues.toPage(), nil
+}
+
+// collectIssues reads pages starting after the given cursor (empty for the
+// first page) until all are read or the client's issue limit is reached
+func (c *Client) collectIssues(ctx context.Context, query string, filterVars map[string]interface{}, after string) ([]Issue, error) {
+	var issues []Issue
+	for {
+		page, err := c.fetchIssuePage(ctx, query, filterVars, after)
+		if err != nil {
+			return nil, err
 		}
+		issues = append(issues, page.Issues...)
+
+		if len(issues) >= c.maxIssues {
+			return issues[:c.maxIssues], nil
+		}
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			return issues, nil
+		}
+		after = page.PageInfo.EndCursor
+	}
+}
+
+// GetIssueUpdatedAt returns when the issue was last updated on Linear
+func (c *Client) GetIssueUpdatedAt(ctx context.Context, issueID string) (string, error) {
+	var result struct {
+		Issue struct {
+			UpdatedAt string `json:"updatedAt"`
+		} `json:"issue"`
+	}
+
+	vars := map[string]interface{}{"issueId": issueID}
+	if err := c.execute(ctx, issueUpdatedAtQuery, vars, &result); err != nil {
+		return "", err
 	}
 
-	return issues, nil
+	return result.Issue.UpdatedAt, nil
 }
 
-func (c *Client) GetIssueWithContext(issueID string) (*Issue, error) {
+func (c *Client) GetIssueWithContext(ctx context.Context, issueID string) (*Issue, error) {
 	var result struct {
 		Issue struct {
-			ID          string                 `json:"id"`
-			Identifier  string                 `json:"identifier"`
-			Title       string                 `json:"title"`
-			Description string                 `json:"description"`
-			Priority    int                    `json:"priority"`
-			BranchName  string                 `json:"branchName"`
-			URL         string                 `json:"url"`
-			State       State                  `json:"state"`
-			Assignee    *User                  `json:"assignee"`
+			ID          string `json:"id"`
+			Identifier  string `json:"identifier"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Priority    int    `json:"priority"`
+			BranchName  string `json:"branchName"`
+			URL         string `json:"url"`
+			UpdatedAt   string `json:"updatedAt"`
+			State       State  `json:"state"`
+			Assignee    *User  `json:"assignee"`
 			Labels      struct {
 				Nodes []Label `json:"nodes"`
 			} `json:"labels"`
@@ -424,7 +633,7 @@ func (c *Client) GetIssueWithContext(issueID string) (*Issue, error) {
 	}
 
 	vars := map[string]interface{}{"issueId": issueID}
-	if err := c.execute(issueWithContextQuery, vars, &result); err != nil {
+	if err := c.execute(ctx, issueWithContextQuery, vars, &result); err != nil {
 		return nil, err
 	}
 
@@ -459,6 +668,7 @@ func (c *Client) GetIssueWithContext(issueID string) (*Issue, error) {
 		Priority:    result.Issue.Priority,
 		BranchName:  result.Issue.BranchName,
 		URL:         result.Issue.URL,
+		UpdatedAt:   result.Issue.UpdatedAt,
 		State:       result.Issue.State,
 		Assignee:    result.Issue.Assignee,
 		Labels:      result.Issue.Labels.Nodes,
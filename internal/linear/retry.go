@@ -0,0 +1,122 @@
+package linear
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a single HTTP request to the Linear API
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxRetries is how many times a failed request is retried
+	DefaultMaxRetries = 3
+
+	baseBackoff = 500 * time.Millisecond
+	maxBackoff  = 8 * time.Second
+
+	// maxRateLimitWait is the longest linc will pause for a rate limit reset
+	// before giving up and returning an error
+	maxRateLimitWait = time.Minute
+)
+
+// RetryEvent describes a failed request that is about to be retried
+type RetryEvent struct {
+	Attempt     int           // attempt that failed, starting at 1
+	MaxAttempts int           // total attempts that will be made
+	Wait        time.Duration // delay before the next attempt
+	RateLimited bool          // true when waiting for a rate limit reset
+	Err         error         // error of the failed attempt, nil for a proactive rate limit pause
+}
+
+func (e RetryEvent) String() string {
+	wait := int(e.Wait.Round(time.Second) / time.Second)
+	if wait < 1 {
+		wait = 1
+	}
+	if e.RateLimited {
+		return fmt.Sprintf("rate limited, retrying in %ds", wait)
+	}
+	return fmt.Sprintf("request failed (attempt %d/%d), retrying in %ds", e.Attempt, e.MaxAttempts, wait)
+}
+
+// backoff returns the jittered exponential delay before the given retry
+func backoff(attempt int) time.Duration {
+	d := baseBackoff << (attempt - 1)
+	if d > maxBackoff || d <= 0 {
+		d = maxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimiter tracks Linear's rate limit headers and pauses requests once
+// the remaining quota is exhausted
+type rateLimiter struct {
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// update records the rate limit state reported by a response
+func (l *rateLimiter) update(header http.Header) {
+	for _, kind := range []string{"Requests", "Complexity"} {
+		remaining, err := strconv.Atoi(header.Get("X-RateLimit-" + kind + "-Remaining"))
+		if err != nil || remaining > 0 {
+			continue
+		}
+		if reset, ok := parseResetHeader(header.Get("X-RateLimit-" + kind + "-Reset")); ok {
+			l.mu.Lock()
+			if reset.After(l.pausedUntil) {
+				l.pausedUntil = reset
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+// delay returns how long requests must wait for the quota to reset
+func (l *rateLimiter) delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return time.Until(l.pausedUntil)
+}
+
+// retryAfter returns how long to wait after a rate limited response,
+// or zero if the response does not say
+func retryAfter(header http.Header) time.Duration {
+	if secs, err := strconv.Atoi(header.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	for _, kind := range []string{"Requests", "Complexity"} {
+		if reset, ok := parseResetHeader(header.Get("X-RateLimit-" + kind + "-Reset")); ok {
+			if d := time.Until(reset); d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
+
+// parseResetHeader parses a rate limit reset timestamp in epoch milliseconds
+func parseResetHeader(value string) (time.Time, bool) {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
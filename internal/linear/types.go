@@ -1,9 +1,10 @@
 package linear
 
 type User struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"` // the user's handle, e.g. "ada"
+	Email       string `json:"email"`
 }
 
 type Team struct {
@@ -52,6 +53,8 @@ type Issue struct {
 	BranchName  string       `json:"branchName"`
 	URL         string       `json:"url"`
 	CreatedAt   string       `json:"createdAt"`
+	UpdatedAt   string       `json:"updatedAt"`
+	ArchivedAt  string       `json:"archivedAt,omitempty"` // set once archived or deleted
 	State       State        `json:"state"`
 	Assignee    *User        `json:"assignee"`
 	Labels      []Label      `json:"labels"`
@@ -92,6 +95,18 @@ type ViewerResponse struct {
 	} `json:"viewer"`
 }
 
+// PageInfo describes where a page sits within a paginated connection
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// IssuePage is a single page of issues along with its pagination cursor
+type IssuePage struct {
+	Issues   []Issue
+	PageInfo PageInfo
+}
+
 type IssuesResponse struct {
 	Issues struct {
 		Nodes []Issue `json:"nodes"`
@@ -112,3 +127,18 @@ type CreateCommentResponse struct {
 		Comment Comment `json:"comment"`
 	} `json:"commentCreate"`
 }
+
+// TeamSnapshot is a team's workflow states and the first pages of its open
+// issues and of those assigned to the viewer
+type TeamSnapshot struct {
+	States   []State
+	Issues   IssuePage
+	MyIssues IssuePage
+}
+
+// TeamUpdates is a team's workflow states and the issues changed since a
+// previous sync
+type TeamUpdates struct {
+	States []State
+	Issues []Issue
+}
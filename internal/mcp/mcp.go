@@ -0,0 +1,58 @@
+// Package mcp writes the temporary MCP server configuration handed to agents,
+// giving them access to Linear during a session.
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultLinearURL is Linear's hosted MCP server
+const DefaultLinearURL = "https://mcp.linear.app/mcp"
+
+// server is an entry of the mcpServers config understood by Claude Code and
+// other MCP clients
+type server struct {
+	Type    string            `json:"type"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// WriteLinearConfig writes an MCP config for the Linear MCP server at url,
+// authenticated with the API key, to a temporary file only readable by the
+// user. The caller removes the file once the session ends.
+func WriteLinearConfig(identifier, apiKey, url string) (string, error) {
+	if url == "" {
+		url = DefaultLinearURL
+	}
+	config := map[string]interface{}{
+		"mcpServers": map[string]server{
+			"linear": {
+				Type:    "http",
+				URL:     url,
+				Headers: map[string]string{"Authorization": "Bearer " + apiKey},
+			},
+		},
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	// CreateTemp creates the file with mode 0600, keeping the key private
+	f, err := os.CreateTemp("", "linc-mcp-"+strings.ToLower(identifier)+"-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to write MCP config: %w", err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write MCP config: %w", err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,91 @@
+package aider
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"linc/internal/config"
+	"linc/internal/git"
+	"linc/internal/provider"
+)
+
+// Provider implements the aider agent provider. aider works through the issue
+// prompt as a single message and exits; it is not an interactive session.
+type Provider struct {
+	addFiles bool
+	planMode string
+}
+
+// New creates a new aider provider; cfg may be nil
+func New(cfg *config.AiderConfig) (*Provider, error) {
+	p := &Provider{planMode: config.AiderAsk}
+	if cfg == nil {
+		return p, nil
+	}
+	p.addFiles = cfg.AddReferencedFiles
+	switch cfg.PlanMode {
+	case "":
+	case config.AiderAsk, config.AiderArchitect:
+		p.planMode = cfg.PlanMode
+	default:
+		return nil, fmt.Errorf("aider.planMode must be %q or %q, not %q", config.AiderAsk, config.AiderArchitect, cfg.PlanMode)
+	}
+	return p, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "aider"
+}
+
+// Available reports whether aider is installed
+func (p *Provider) Available() error {
+	return provider.LookPath("aider")
+}
+
+// Capabilities returns what the aider provider supports
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{PlanMode: true}
+}
+
+// Exec runs aider with the issue prompt as its message and returns once it
+// exits. With --message-file aider handles that one message and quits
+// rather than opening a chat; the file is used as the prompt is often too
+// long for the command line.
+func (p *Provider) Exec(req provider.Request) error {
+	aiderPath, err := exec.LookPath("aider")
+	if err != nil {
+		return fmt.Errorf("aider not found in PATH: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "linc-"+strings.ToLower(req.Issue.Identifier)+"-*.md")
+	if err != nil {
+		return fmt.Errorf("failed to write message file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(req.Prompt)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write message file: %w", err)
+	}
+
+	args := []string{"--message-file", f.Name()}
+	if req.PlanMode {
+		// ask answers questions about the code, architect proposes changes
+		// for an editor model to apply
+		args = append(args, "--chat-mode", p.planMode)
+	}
+	if p.addFiles {
+		args = append(args, referencedFiles(req.Issue, git.GetRepoRoot())...)
+	}
+
+	cmd := exec.Command(aiderPath, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return provider.RunCommand(cmd)
+}
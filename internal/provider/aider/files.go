@@ -0,0 +1,59 @@
+package aider
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"linc/internal/linear"
+)
+
+// pathPattern matches path-like tokens such as `internal/git/git.go` or main.go
+var pathPattern = regexp.MustCompile(`[\w.\-/]*[\w\-]\.[A-Za-z0-9]+`)
+
+// blobPattern matches the file path of GitHub and GitLab file links
+var blobPattern = regexp.MustCompile(`/(?:-/)?blob/[^/]+/(.+)$`)
+
+// referencedFiles returns the files of the repository at root that are
+// mentioned in the issue description or linked from its attachments, as
+// paths relative to the working directory
+func referencedFiles(issue linear.Issue, root string) []string {
+	if root == "" {
+		return nil
+	}
+
+	candidates := pathPattern.FindAllString(issue.Description, -1)
+	for _, att := range issue.Attachments {
+		if u, err := url.Parse(att.URL); err == nil {
+			if m := blobPattern.FindStringSubmatch(u.Path); m != nil {
+				candidates = append(candidates, m[1])
+			}
+		}
+		candidates = append(candidates, pathPattern.FindAllString(att.Title, -1)...)
+	}
+
+	cwd, _ := os.Getwd()
+	seen := make(map[string]bool)
+	var files []string
+	for _, candidate := range candidates {
+		candidate = strings.TrimLeft(strings.TrimPrefix(candidate, "./"), "/")
+		if candidate == "" || strings.Contains(candidate, "..") {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(candidate))
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if rel, err := filepath.Rel(cwd, path); err == nil && cwd != "" {
+			path = rel
+		}
+		files = append(files, path)
+	}
+	return files
+}
@@ -6,7 +6,6 @@ import (
 	"os/exec"
 	"syscall"
 
-	"linc/internal/linear"
 	"linc/internal/provider"
 )
 
@@ -23,20 +22,58 @@ func (p *Provider) Name() string {
 	return "Claude Code"
 }
 
-// Exec launches Claude Code with the issue context
-// This replaces the current process with Claude
-func (p *Provider) Exec(issue linear.Issue, comment string, ctx *linear.IssueContext, planMode bool) error {
-	prompt := provider.BuildPrompt(issue, comment, ctx)
+// Available reports whether claude is installed
+func (p *Provider) Available() error {
+	return provider.LookPath("claude")
+}
 
+// Capabilities returns what the claude provider supports
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		PlanMode:      true,
+		Resume:        true,
+		MCPConfig:     true,
+		MaxPromptSize: provider.MaxArgSize,
+	}
+}
+
+// Command returns the command that launches Claude Code with the issue context
+func (p *Provider) Command(req provider.Request) (*exec.Cmd, error) {
 	claudePath, err := exec.LookPath("claude")
 	if err != nil {
-		return fmt.Errorf("claude not found in PATH: %w", err)
+		return nil, fmt.Errorf("claude not found in PATH: %w", err)
 	}
 
-	args := []string{"claude", prompt}
-	if planMode {
+	args := []string{"claude"}
+	switch {
+	case req.Resume:
+		// The session already has the issue; only the new notes are sent
+		args = append(args, "--resume", req.SessionID)
+		if req.Comment != "" {
+			args = append(args, req.Comment)
+		}
+	case req.SessionID != "":
+		args = append(args, "--session-id", req.SessionID, req.Prompt)
+	default:
+		args = append(args, req.Prompt)
+	}
+	if req.PlanMode {
 		args = append(args, "--permission-mode", "plan")
 	}
+	if req.MCPConfig != "" {
+		// --mcp-config takes several files, so it goes after the prompt
+		args = append(args, "--mcp-config", req.MCPConfig)
+	}
+
+	return &exec.Cmd{Path: claudePath, Args: args}, nil
+}
 
-	return syscall.Exec(claudePath, args, os.Environ())
+// Exec launches Claude Code with the issue context
+// This replaces the current process with Claude
+func (p *Provider) Exec(req provider.Request) error {
+	cmd, err := p.Command(req)
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(cmd.Path, cmd.Args, os.Environ())
 }
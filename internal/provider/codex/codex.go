@@ -0,0 +1,68 @@
+package codex
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"linc/internal/provider"
+)
+
+// Binary is the executable of the OpenAI Codex CLI
+const Binary = "codex"
+
+// Provider implements the OpenAI Codex CLI agent provider
+type Provider struct{}
+
+// New creates a new Codex provider
+func New() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Codex CLI"
+}
+
+// Available reports whether codex is installed
+func (p *Provider) Available() error {
+	return provider.LookPath(Binary)
+}
+
+// Capabilities returns what the codex provider supports
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		PlanMode:      true,
+		MaxPromptSize: provider.MaxArgSize,
+	}
+}
+
+// Command returns the command that launches Codex's TUI with the issue prompt
+// as the first message
+func (p *Provider) Command(req provider.Request) (*exec.Cmd, error) {
+	codexPath, err := exec.LookPath(Binary)
+	if err != nil {
+		return nil, fmt.Errorf("codex not found in PATH: %w", err)
+	}
+
+	args := []string{"codex"}
+	if req.PlanMode {
+		// The read-only sandbox lets Codex read the repository but not edit
+		// files or run commands that write
+		args = append(args, "--sandbox", "read-only")
+	}
+	args = append(args, req.Prompt)
+
+	return &exec.Cmd{Path: codexPath, Args: args}, nil
+}
+
+// Exec launches Codex's TUI with the issue prompt as the first message
+// This replaces the current process with Codex
+func (p *Provider) Exec(req provider.Request) error {
+	cmd, err := p.Command(req)
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(cmd.Path, cmd.Args, os.Environ())
+}
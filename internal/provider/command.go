@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+
+	"linc/internal/config"
+)
+
+// Prompt delivery modes of command providers
+const (
+	PromptArg   = "arg"
+	PromptStdin = "stdin"
+	PromptFile  = "file"
+)
+
+// CommandProvider runs a command declared in the config, see
+// config.ProviderConfig
+type CommandProvider struct {
+	name   string
+	binary string
+	args   []*template.Template
+	prompt string
+	env    map[string]*template.Template
+	caps   Capabilities
+}
+
+// commandData is the data available to command provider templates
+type commandData struct {
+	Prompt     string
+	PromptFile string
+	Identifier string
+	Title      string
+	URL        string
+	BranchName string
+	PlanMode   bool
+}
+
+// NewCommandProvider parses a provider declared in the config
+func NewCommandProvider(id string, cfg config.ProviderConfig) (*CommandProvider, error) {
+	if cfg.Binary == "" {
+		return nil, fmt.Errorf("provider %s: binary is required", id)
+	}
+
+	p := &CommandProvider{
+		name:   cfg.Name,
+		binary: cfg.Binary,
+		prompt: cfg.Prompt,
+		env:    make(map[string]*template.Template),
+	}
+	if p.name == "" {
+		p.name = id
+	}
+	switch p.prompt {
+	case "":
+		p.prompt = PromptArg
+	case PromptArg, PromptStdin, PromptFile:
+	default:
+		return nil, fmt.Errorf("provider %s: unknown prompt delivery %q (use arg, stdin or file)", id, cfg.Prompt)
+	}
+
+	for i, arg := range cfg.Args {
+		tmpl, err := template.New(fmt.Sprintf("%s arg %d", id, i)).Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", id, err)
+		}
+		p.args = append(p.args, tmpl)
+	}
+	for key, value := range cfg.Env {
+		tmpl, err := template.New(id + " env " + key).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", id, err)
+		}
+		p.env[key] = tmpl
+	}
+
+	// Passed as an argument, the prompt goes last unless a template places it
+	if p.prompt == PromptArg && !references(cfg.Args, ".Prompt") {
+		p.args = append(p.args, template.Must(template.New(id+" prompt").Parse("{{.Prompt}}")))
+	}
+
+	// Plan mode is supported if the command is told about it
+	env := make([]string, 0, len(cfg.Env))
+	for _, value := range cfg.Env {
+		env = append(env, value)
+	}
+	p.caps.PlanMode = references(cfg.Args, ".PlanMode") || references(env, ".PlanMode")
+	if p.prompt == PromptArg {
+		p.caps.MaxPromptSize = MaxArgSize
+	}
+
+	return p, nil
+}
+
+func references(templates []string, field string) bool {
+	for _, text := range templates {
+		if strings.Contains(text, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the provider's display name
+func (p *CommandProvider) Name() string {
+	return p.name
+}
+
+// Available reports whether the command's binary is installed
+func (p *CommandProvider) Available() error {
+	return LookPath(p.binary)
+}
+
+// Capabilities returns what the command supports, derived from its templates
+func (p *CommandProvider) Capabilities() Capabilities {
+	return p.caps
+}
+
+// Exec runs the command in the foreground and returns once it exits
+func (p *CommandProvider) Exec(req Request) error {
+	binary, err := exec.LookPath(p.binary)
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH: %w", p.binary, err)
+	}
+
+	data := commandData{
+		Prompt:     req.Prompt,
+		Identifier: req.Issue.Identifier,
+		Title:      req.Issue.Title,
+		URL:        req.Issue.URL,
+		BranchName: req.Issue.BranchName,
+		PlanMode:   req.PlanMode,
+	}
+
+	if p.prompt == PromptFile {
+		f, err := os.CreateTemp("", "linc-"+strings.ToLower(req.Issue.Identifier)+"-*.md")
+		if err != nil {
+			return fmt.Errorf("failed to write prompt file: %w", err)
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(data.Prompt)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write prompt file: %w", err)
+		}
+		data.PromptFile = f.Name()
+	}
+
+	args := make([]string, 0, len(p.args))
+	for _, tmpl := range p.args {
+		arg, err := render(tmpl, data)
+		if err != nil {
+			return err
+		}
+		// Conditional arguments like {{if .PlanMode}}--plan{{end}} may render empty
+		if arg != "" {
+			args = append(args, arg)
+		}
+	}
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if p.prompt == PromptStdin {
+		cmd.Stdin = strings.NewReader(data.Prompt)
+	}
+
+	cmd.Env = os.Environ()
+	for key, tmpl := range p.env {
+		value, err := render(tmpl, data)
+		if err != nil {
+			return err
+		}
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	return RunCommand(cmd)
+}
+
+func render(tmpl *template.Template, data commandData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -3,7 +3,6 @@ package echo
 import (
 	"fmt"
 
-	"linc/internal/linear"
 	"linc/internal/provider"
 )
 
@@ -21,12 +20,22 @@ func (p *Provider) Name() string {
 	return "Echo (test)"
 }
 
+// Available reports nil, echo needs nothing installed
+func (p *Provider) Available() error {
+	return nil
+}
+
+// Capabilities returns what the echo provider supports
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{PlanMode: true}
+}
+
 // Exec prints the prompt to stdout
-func (p *Provider) Exec(issue linear.Issue, comment string, ctx *linear.IssueContext, planMode bool) error {
-	prompt := provider.BuildPrompt(issue, comment, ctx)
+func (p *Provider) Exec(req provider.Request) error {
+	prompt := req.Prompt
 
 	fmt.Println("=== Echo Provider Output ===")
-	fmt.Printf("Plan Mode: %v\n", planMode)
+	fmt.Printf("Plan Mode: %v\n", req.PlanMode)
 	fmt.Println("=== Prompt Start ===")
 	fmt.Println(prompt)
 	fmt.Println("=== Prompt End ===")
@@ -0,0 +1,62 @@
+package gemini
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"linc/internal/provider"
+)
+
+// Binary is the executable of the Gemini CLI
+const Binary = "gemini"
+
+// Provider implements the Gemini CLI agent provider
+type Provider struct{}
+
+// New creates a new Gemini provider
+func New() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Gemini CLI"
+}
+
+// Available reports whether gemini is installed
+func (p *Provider) Available() error {
+	return provider.LookPath(Binary)
+}
+
+// Capabilities returns what the gemini provider supports. Gemini CLI has no
+// approval mode that keeps it from editing, so there is no plan mode.
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		MaxPromptSize: provider.MaxArgSize,
+	}
+}
+
+// Command returns the command that launches Gemini CLI's interactive session
+// with the issue prompt as the first message
+func (p *Provider) Command(req provider.Request) (*exec.Cmd, error) {
+	geminiPath, err := exec.LookPath(Binary)
+	if err != nil {
+		return nil, fmt.Errorf("gemini not found in PATH: %w", err)
+	}
+
+	args := []string{"gemini", "--prompt-interactive", req.Prompt}
+	return &exec.Cmd{Path: geminiPath, Args: args}, nil
+}
+
+// Exec launches Gemini CLI's interactive session with the issue prompt as
+// the first message
+// This replaces the current process with Gemini CLI
+func (p *Provider) Exec(req provider.Request) error {
+	cmd, err := p.Command(req)
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(cmd.Path, cmd.Args, os.Environ())
+}
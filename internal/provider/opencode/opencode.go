@@ -0,0 +1,62 @@
+package opencode
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"linc/internal/provider"
+)
+
+// Provider implements the opencode agent provider
+type Provider struct{}
+
+// New creates a new opencode provider
+func New() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "opencode"
+}
+
+// Available reports whether opencode is installed
+func (p *Provider) Available() error {
+	return provider.LookPath("opencode")
+}
+
+// Capabilities returns what the opencode provider supports
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		PlanMode:      true,
+		MaxPromptSize: provider.MaxArgSize,
+	}
+}
+
+// Command returns the command that launches opencode's TUI with the issue prompt prefilled
+func (p *Provider) Command(req provider.Request) (*exec.Cmd, error) {
+	opencodePath, err := exec.LookPath("opencode")
+	if err != nil {
+		return nil, fmt.Errorf("opencode not found in PATH: %w", err)
+	}
+
+	args := []string{"opencode", "--prompt", req.Prompt}
+	if req.PlanMode {
+		// opencode's plan agent analyses and proposes changes without editing files
+		args = append(args, "--agent", "plan")
+	}
+
+	return &exec.Cmd{Path: opencodePath, Args: args}, nil
+}
+
+// Exec launches opencode's TUI with the issue prompt prefilled
+// This replaces the current process with opencode
+func (p *Provider) Exec(req provider.Request) error {
+	cmd, err := p.Command(req)
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(cmd.Path, cmd.Args, os.Environ())
+}
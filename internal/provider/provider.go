@@ -1,6 +1,10 @@
 package provider
 
 import (
+	"fmt"
+	"os/exec"
+	"text/template"
+
 	"linc/internal/linear"
 )
 
@@ -9,13 +13,88 @@ type Provider interface {
 	// Name returns the provider's display name
 	Name() string
 
-	// Exec launches the provider with the given issue context
+	// Available reports why the provider can't run, e.g. a missing binary,
+	// or nil if it can
+	Available() error
+
+	// Capabilities describes what the provider supports
+	Capabilities() Capabilities
+
+	// Exec launches the provider for the request
 	// This may replace the current process (syscall.Exec) or return after completion
-	Exec(issue linear.Issue, comment string, ctx *linear.IssueContext, planMode bool) error
+	Exec(req Request) error
+}
+
+// Request describes the work a provider is started for
+type Request struct {
+	Issue    linear.Issue
+	Comment  string
+	Context  *linear.IssueContext
+	PlanMode bool
+	Prompt   string // the rendered prompt template
+
+	// SessionID identifies the agent session to start, or with Resume the
+	// one to continue, for providers that can resume sessions
+	SessionID string
+	Resume    bool
+
+	// MCPConfig is the path of an MCP server config for the session, for
+	// providers that accept one
+	MCPConfig string
+}
+
+// Capabilities describes what a provider supports
+type Capabilities struct {
+	PlanMode      bool // honours Request.PlanMode
+	Resume        bool // can resume a previous session
+	MCPConfig     bool // accepts an MCP server config
+	MaxPromptSize int  // longest prompt in bytes, 0 if unlimited
 }
 
-// BuildPrompt creates a standardized prompt from issue data
-// This can be used by any provider that needs a text prompt
+// MaxArgSize is the longest single command-line argument on Linux, which
+// limits prompts passed as an argument
+const MaxArgSize = 128*1024 - 1
+
+// LookPath reports an error if binary is not on PATH, for Available
+// implementations
+func LookPath(binary string) error {
+	if _, err := exec.LookPath(binary); err != nil {
+		return fmt.Errorf("%s not found in PATH", binary)
+	}
+	return nil
+}
+
+// Check reports why p can't be started for req. It is run before Linear is
+// updated so nothing changes when the agent can't start.
+func Check(p Provider, req Request) error {
+	return check(p.Name(), p.Available(), p.Capabilities(), req)
+}
+
+func check(name string, availableErr error, caps Capabilities, req Request) error {
+	if availableErr != nil {
+		return fmt.Errorf("%s is not available: %w", name, availableErr)
+	}
+	if req.Resume && !caps.Resume {
+		return fmt.Errorf("%s can't resume sessions", name)
+	}
+	if req.PlanMode && !caps.PlanMode {
+		return fmt.Errorf("%s does not support plan mode", name)
+	}
+	if caps.MaxPromptSize > 0 && !req.Resume && len(req.Prompt) > caps.MaxPromptSize {
+		return fmt.Errorf("prompt is %d bytes, %s accepts at most %d", len(req.Prompt), name, caps.MaxPromptSize)
+	}
+	return nil
+}
+
+var defaultTemplate = template.Must(template.New(DefaultTemplate).Funcs(templateFuncs).Parse(defaultTemplateText))
+
+// BuildPrompt creates a prompt from issue data with the built-in default
+// template, see LoadTemplate for user-defined templates
 func BuildPrompt(issue linear.Issue, comment string, ctx *linear.IssueContext) string {
-	return buildPromptInternal(issue, comment, ctx)
+	prompt, err := RenderPrompt(defaultTemplate, PromptData{Issue: issue, Context: ctx, Comment: comment})
+	if err != nil {
+		// The built-in template only fails on a bug in linc
+		panic(err)
+	}
+	return prompt
 }
@@ -1,7 +1,11 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+
+	"linc/internal/config"
 )
 
 // Registry holds all available providers
@@ -23,6 +27,14 @@ func (r *Registry) Register(id string, p Provider) {
 	r.providers[id] = p
 }
 
+// RegisterIfAvailable adds a provider only if it is available, so agents
+// that aren't installed stay out of the settings picker
+func (r *Registry) RegisterIfAvailable(id string, p Provider) {
+	if p.Available() == nil {
+		r.Register(id, p)
+	}
+}
+
 // Get returns a provider by ID
 func (r *Registry) Get(id string) (Provider, error) {
 	p, ok := r.providers[id]
@@ -56,7 +68,54 @@ func (r *Registry) List() []string {
 	return ids
 }
 
+// Info describes a registered provider for display
+type Info struct {
+	ID           string
+	Name         string
+	Capabilities Capabilities
+	Err          error // why the provider is unavailable, nil if it is available
+}
+
+// Check reports why the described provider can't be started for req, like
+// the package's Check, from what was known when it was described. The
+// prompt size is only checked if req has a prompt.
+func (i Info) Check(req Request) error {
+	return check(i.Name, i.Err, i.Capabilities, req)
+}
+
+// Describe returns all registered providers sorted by ID, checking whether
+// each is available
+func (r *Registry) Describe() []Info {
+	infos := make([]Info, 0, len(r.providers))
+	for id, p := range r.providers {
+		infos = append(infos, Info{
+			ID:           id,
+			Name:         p.Name(),
+			Capabilities: p.Capabilities(),
+			Err:          p.Available(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
 // DefaultID returns the default provider ID
 func (r *Registry) DefaultID() string {
 	return r.defaultID
 }
+
+// RegisterCommands adds the custom providers declared in the config. A
+// custom provider with a built-in's ID replaces it. Invalid declarations
+// are skipped and reported together.
+func (r *Registry) RegisterCommands(providers map[string]config.ProviderConfig) error {
+	var errs []error
+	for id, cfg := range providers {
+		p, err := NewCommandProvider(id, cfg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		r.Register(id, p)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+)
+
+// Commander is implemented by providers that replace linc's process in Exec.
+// Command returns the same invocation so it can run as a child process
+// instead, see Run.
+type Commander interface {
+	Command(req Request) (*exec.Cmd, error)
+}
+
+// Run runs the provider as a child process with the terminal handed over and
+// returns once the session ends, so linc can carry on afterwards. Providers
+// that aren't Commanders already return from Exec when done.
+func Run(p Provider, req Request) error {
+	c, ok := p.(Commander)
+	if !ok {
+		return p.Exec(req)
+	}
+	cmd, err := c.Command(req)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Ctrl+C is meant for the agent, which shares the terminal; linc keeps
+	// running to offer follow-ups
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	return RunCommand(cmd)
+}
+
+// ExitError reports an agent that exited with a non-zero status
+type ExitError struct {
+	Binary string
+	Code   int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("%s exited with status %d", e.Binary, e.Code)
+}
+
+// RunCommand runs cmd, reporting a non-zero exit status as an ExitError
+func RunCommand(cmd *exec.Cmd) error {
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &ExitError{Binary: filepath.Base(cmd.Path), Code: exitErr.ExitCode()}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"linc/internal/linear"
+)
+
+// DefaultTemplate is the name of the built-in prompt template. A template
+// file of the same name overrides it.
+const DefaultTemplate = "default"
+
+//go:embed templates/default.tmpl
+var defaultTemplateText string
+
+// PromptData is the data prompt templates are rendered with
+type PromptData struct {
+	Issue   linear.Issue
+	Context *linear.IssueContext
+	Comment string // the user's notes from the start work screen
+	Git     GitInfo
+}
+
+// GitInfo describes the repository linc was started in
+type GitInfo struct {
+	Root   string // repository root, empty outside a repository
+	Branch string // current branch
+}
+
+var templateFuncs = template.FuncMap{
+	"join": func(sep string, items []string) string { return strings.Join(items, sep) },
+	"labelNames": func(labels []linear.Label) []string {
+		names := make([]string, len(labels))
+		for i, label := range labels {
+			names[i] = label.Name
+		}
+		return names
+	},
+	"slackAttachments": func(attachments []linear.Attachment) []linear.Attachment {
+		slack, _ := filterSlackAttachments(attachments)
+		return slack
+	},
+	"otherAttachments": func(attachments []linear.Attachment) []linear.Attachment {
+		_, other := filterSlackAttachments(attachments)
+		return other
+	},
+	"slackContent": extractSlackContent,
+	"date":         formatCommentDate,
+	// quote formats text as a Markdown block quote
+	"quote": func(text string) string {
+		return "> " + strings.ReplaceAll(text, "\n", "\n> ")
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// TemplateDirs returns the directories prompt templates are loaded from, in
+// order of precedence: .linc/templates in the repository, then
+// ~/.linc/templates
+func TemplateDirs(repoRoot string) []string {
+	var dirs []string
+	if repoRoot != "" {
+		dirs = append(dirs, filepath.Join(repoRoot, ".linc", "templates"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".linc", "templates"))
+	}
+	return dirs
+}
+
+// LoadTemplate parses the template <name>.tmpl from the first directory
+// containing it, falling back to the built-in default template
+func LoadTemplate(name string, dirs []string) (*template.Template, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name+".tmpl")
+		text, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse prompt template %s: %w", path, err)
+		}
+		return tmpl, nil
+	}
+
+	if name != DefaultTemplate {
+		return nil, fmt.Errorf("prompt template %q not found in %s", name, strings.Join(dirs, ", "))
+	}
+	return defaultTemplate, nil
+}
+
+// RenderPrompt renders a prompt template
+func RenderPrompt(tmpl *template.Template, data PromptData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,295 @@
+// Package session records the agent sessions started on issues, so linc can
+// show which issues were worked on, with which agent and when.
+package session
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"linc/internal/config"
+	"linc/internal/provider"
+)
+
+// Session is one start-work session of an agent on an issue
+type Session struct {
+	ID         string    `json:"id"`
+	IssueID    string    `json:"issueId"`
+	Identifier string    `json:"identifier"`
+	Provider   string    `json:"provider"` // provider ID
+	PlanMode   bool      `json:"planMode"`
+	Branch     string    `json:"branch,omitempty"`
+	Dir        string    `json:"dir,omitempty"` // directory the agent ran in
+	StartedAt  time.Time `json:"startedAt"`
+
+	// AgentSessionID is the agent's own ID for the session, set for
+	// providers that can resume sessions
+	AgentSessionID string `json:"agentSessionId,omitempty"`
+
+	// PID is the linc process that started the agent, which the agent
+	// replaces when linc hands its process over
+	PID int `json:"pid,omitempty"`
+
+	// EndedAt is zero while the agent runs. When linc handed its process
+	// over, it never sees the agent exit, so EndedAt and HeadAfter are
+	// filled in once linc finds the process gone, and EndUnseen is set.
+	EndedAt   time.Time `json:"endedAt,omitempty"`
+	EndUnseen bool      `json:"endUnseen,omitempty"`
+	ExitCode  *int      `json:"exitCode,omitempty"`
+	Error     string    `json:"error,omitempty"` // why the agent could not run, or its exit status
+
+	HeadBefore string `json:"headBefore,omitempty"`
+	HeadAfter  string `json:"headAfter,omitempty"`
+}
+
+// NewAgentID returns a random UUID to start an agent session with
+func NewAgentID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// Resumable returns the most recent of an issue's sessions that the provider
+// can resume from one of dirs, or nil. Agents keep sessions per directory.
+func Resumable(sessions []Session, providerID string, dirs ...string) *Session {
+	for i := len(sessions) - 1; i >= 0; i-- {
+		sess := sessions[i]
+		if sess.AgentSessionID == "" || sess.Provider != providerID {
+			continue
+		}
+		for _, dir := range dirs {
+			if sess.Dir == dir {
+				return &sess
+			}
+		}
+	}
+	return nil
+}
+
+// Ended records how the session ended, err being the result of running the
+// agent
+func (s *Session) Ended(err error, head string) {
+	s.EndedAt = time.Now()
+	s.HeadAfter = head
+	code := 0
+	if err != nil {
+		s.Error = err.Error()
+		var exitErr *provider.ExitError
+		if !errors.As(err, &exitErr) {
+			// The agent didn't run
+			return
+		}
+		code = exitErr.Code
+	}
+	s.ExitCode = &code
+}
+
+// agentRunning reports whether the process the agent runs in, or under, is
+// still alive. Without a PID it can't be told, and the agent is taken to be
+// gone.
+func (s Session) agentRunning() bool {
+	if s.PID == 0 || s.PID == os.Getpid() {
+		return false
+	}
+	proc, err := os.FindProcess(s.PID)
+	if err != nil {
+		return false
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// Status describes how the session ended
+func (s Session) Status() string {
+	switch {
+	case s.EndUnseen:
+		return "handed over, ended unseen"
+	case s.EndedAt.IsZero():
+		return "handed over, running"
+	case s.ExitCode == nil:
+		return "failed"
+	default:
+		return fmt.Sprintf("exit %d", *s.ExitCode)
+	}
+}
+
+// Duration returns how long the session ran, or 0 if it is not known
+func (s Session) Duration() time.Duration {
+	if s.EndedAt.IsZero() || s.EndUnseen {
+		return 0
+	}
+	return s.EndedAt.Sub(s.StartedAt)
+}
+
+// Commits describes the HEAD commits before and after the session, e.g.
+// "1a2b3c4..5d6e7f8"
+func (s Session) Commits() string {
+	before, after := shortHash(s.HeadBefore), shortHash(s.HeadAfter)
+	switch {
+	case after == "":
+		return before
+	case before == after:
+		return before + " (no commits)"
+	default:
+		return before + ".." + after
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// Store keeps sessions in ~/.linc/sessions, one file per issue
+type Store struct {
+	dir string
+}
+
+// Open returns the session store. Nothing is created on disk until a
+// session is saved.
+func Open() (*Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: filepath.Join(dir, "sessions")}, nil
+}
+
+// Save records a new session or updates an existing one. New sessions get an
+// ID and start time.
+func (s *Store) Save(sess *Session) error {
+	if sess.StartedAt.IsZero() {
+		sess.StartedAt = time.Now()
+	}
+	if sess.ID == "" {
+		sess.ID = strconv.FormatInt(sess.StartedAt.UnixNano(), 36)
+	}
+
+	sessions, err := s.ForIssue(sess.IssueID)
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range sessions {
+		if sessions[i].ID == sess.ID {
+			sessions[i] = *sess
+			replaced = true
+		}
+	}
+	if !replaced {
+		sessions = append(sessions, *sess)
+	}
+	return s.write(sess.IssueID, sessions)
+}
+
+// CloseUnseen fills in the end of an issue's handed over sessions whose
+// agent has exited, head returning the commit a session's work ended at.
+// EndedAt is then only an upper bound.
+func (s *Store) CloseUnseen(issueID string, head func(Session) string) error {
+	sessions, err := s.ForIssue(issueID)
+	if err != nil {
+		return err
+	}
+	closed := false
+	for i := range sessions {
+		sess := &sessions[i]
+		if !sess.EndedAt.IsZero() || sess.agentRunning() {
+			continue
+		}
+		sess.EndedAt = time.Now()
+		sess.EndUnseen = true
+		sess.HeadAfter = head(*sess)
+		closed = true
+	}
+	if !closed {
+		return nil
+	}
+	return s.write(issueID, sessions)
+}
+
+// ForIssue returns the sessions of an issue, oldest first
+func (s *Store) ForIssue(issueID string) ([]Session, error) {
+	data, err := os.ReadFile(s.path(issueID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var sessions []Session
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to read sessions of %s: %w", issueID, err)
+	}
+	return sessions, nil
+}
+
+// All returns the sessions of all issues, most recent first
+func (s *Store) All() ([]Session, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var all []Session
+	for _, entry := range entries {
+		issueID, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		sessions, err := s.ForIssue(issueID)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, sessions...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].StartedAt.After(all[j].StartedAt) })
+	return all, nil
+}
+
+func (s *Store) path(issueID string) string {
+	return filepath.Join(s.dir, issueID+".json")
+}
+
+// write replaces an issue's session file atomically
+func (s *Store) write(issueID string, sessions []Session) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, issueID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(issueID))
+}
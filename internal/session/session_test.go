@@ -0,0 +1,70 @@
+package session
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCloseUnseen(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Now().Add(-time.Hour)
+	code := 0
+	sessions := []Session{
+		// Handed over by a linc process that is gone
+		{ID: "gone", PID: 0, Branch: "eng-1", HeadBefore: "aaa"},
+		// Still running under the test's parent process
+		{ID: "running", PID: os.Getppid(), Branch: "eng-1", HeadBefore: "bbb"},
+		// Ended while supervised
+		{ID: "ended", PID: 0, EndedAt: started.Add(time.Minute), ExitCode: &code, HeadAfter: "ccc"},
+	}
+	for i := range sessions {
+		sessions[i].IssueID = "issue-1"
+		sessions[i].StartedAt = started
+		if err := store.Save(&sessions[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var asked []string
+	err = store.CloseUnseen("issue-1", func(sess Session) string {
+		asked = append(asked, sess.ID)
+		return "fff"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(asked) != 1 || asked[0] != "gone" {
+		t.Errorf("asked for the heads of %v, want only gone's", asked)
+	}
+
+	got, err := store.ForIssue("issue-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gone := got[0]; gone.EndedAt.IsZero() || !gone.EndUnseen || gone.HeadAfter != "fff" {
+		t.Errorf("gone = %+v, want an unseen end at fff", gone)
+	} else if gone.Status() != "handed over, ended unseen" || gone.Duration() != 0 || gone.Commits() != "aaa..fff" {
+		t.Errorf("gone shows as %q, %s, %q", gone.Status(), gone.Duration(), gone.Commits())
+	}
+	if running := got[1]; !running.EndedAt.IsZero() || running.Status() != "handed over, running" {
+		t.Errorf("running = %+v, want it left running", running)
+	}
+	if ended := got[2]; ended.EndUnseen || ended.HeadAfter != "ccc" || ended.Duration() != time.Minute {
+		t.Errorf("ended = %+v, want it untouched", ended)
+	}
+
+	// Nothing is left to close
+	err = store.CloseUnseen("issue-1", func(Session) string {
+		t.Error("asked for a head with nothing to close")
+		return ""
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	"linc/internal/cache"
+	"linc/internal/journal"
+	"linc/internal/linear"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// syncOverlap is subtracted from sync timestamps so clock skew between
+// linc and Linear can't cause updates to be missed
+const syncOverlap = time.Minute
+
+func syncTimestamp() string {
+	return time.Now().Add(-syncOverlap).UTC().Format(time.RFC3339)
+}
+
+// openCache opens the cache and offline journal of the current workspace and
+// renders the cached viewer and team, if any, until fresh data arrives
+func (m RootModel) openCache() RootModel {
+	m.cache = nil
+	m.journal = nil
+	m.viewerID = ""
+	m.viewerFresh = false
+	m.syncedAt = ""
+	m.snapshotAt = ""
+	if m.workspace == nil {
+		return m
+	}
+
+	if j, err := journal.Open(m.workspace.ID); err == nil {
+		m.journal = j
+	}
+
+	store, err := cache.Open(m.workspace.ID)
+	if err != nil {
+		return m
+	}
+	m.cache = store
+
+	if viewer, err := store.Viewer(); err == nil && viewer != nil {
+		m, _ = m.applyViewer(viewer, false)
+	}
+	return m
+}
+
+// applyViewer picks the team to show for the viewer. When fresh is false the
+// viewer came from the cache and nothing is fetched yet.
+func (m RootModel) applyViewer(viewer *linear.ViewerResponse, fresh bool) (RootModel, tea.Cmd) {
+	m.viewerID = viewer.Viewer.ID
+	m.teams = viewer.Viewer.Teams.Nodes
+
+	// Already showing a team from the cache: revalidate it
+	if m.selectedTeam != nil {
+		for _, team := range m.teams {
+			if team.ID == m.selectedTeam.ID {
+				if !fresh {
+					return m, nil
+				}
+				return m.refreshTeam(team.ID)
+			}
+		}
+		m.selectedTeam = nil
+		m.list = m.newListModel()
+	}
+
+	if m.workspace != nil && m.workspace.DefaultTeamID != "" {
+		for _, team := range m.teams {
+			if team.ID == m.workspace.DefaultTeamID {
+				return m.selectTeam(team, fresh)
+			}
+		}
+	}
+
+	if len(m.teams) == 1 {
+		return m.selectTeam(m.teams[0], fresh)
+	}
+
+	m.teamSelect = m.teamSelect.SetTeams(m.teams)
+	if m.currentView != ViewFollowUp {
+		m.currentView = ViewTeamSelect
+	}
+	return m, nil
+}
+
+// selectTeam shows the team's cached issues, if any, and fetches fresh ones
+// unless the viewer itself still has to be revalidated
+func (m RootModel) selectTeam(team linear.Team, fresh bool) (RootModel, tea.Cmd) {
+	m.selectedTeam = &team
+	if m.currentView != ViewFollowUp {
+		m.currentView = ViewList
+	}
+	m.list = m.newListModel()
+	m.syncedAt = ""
+	m.snapshotAt = ""
+
+	if m.cache != nil {
+		states, _ := m.cache.TeamStates(team.ID)
+		issues, _ := m.cache.TeamIssues(team.ID)
+		if states != nil && issues != nil {
+			m.list = m.list.SetStates(states).
+				SetMyIssues(issues.MyIssues).SetMyPageInfo(issues.MyPageInfo).
+				SetAllIssues(issues.AllIssues).SetAllPageInfo(issues.AllPageInfo).
+				SetRefreshing(true)
+			m.syncedAt = issues.SyncedAt
+			m.snapshotAt = issues.SnapshotAt
+		}
+	}
+
+	if !fresh {
+		return m, nil
+	}
+	return m.refreshTeam(team.ID)
+}
+
+// refreshTeam fetches the team's states and either the issues changed since
+// the last sync or, without a previous sync, the first pages of issues
+func (m RootModel) refreshTeam(teamID string) (RootModel, tea.Cmd) {
+	if m.syncedAt == "" {
+		return m, m.loadTeam(teamID)
+	}
+	m.list = m.list.SetRefreshing(true)
+	return m, m.syncTeam(teamID)
+}
+
+func (m RootModel) saveViewer(viewer *linear.ViewerResponse) tea.Cmd {
+	if m.cache == nil {
+		return nil
+	}
+	store := m.cache
+	return func() tea.Msg {
+		_ = store.SaveViewer(viewer)
+		return nil
+	}
+}
+
+func (m RootModel) saveTeamStates(states []linear.State) tea.Cmd {
+	if m.cache == nil || m.selectedTeam == nil {
+		return nil
+	}
+	store, teamID := m.cache, m.selectedTeam.ID
+	return func() tea.Msg {
+		_ = store.SaveTeamStates(teamID, states)
+		return nil
+	}
+}
+
+// saveTeamIssues writes the list's current issues to the cache, once a
+// sync timestamp is known for them
+func (m RootModel) saveTeamIssues() tea.Cmd {
+	if m.cache == nil || m.selectedTeam == nil || m.syncedAt == "" {
+		return nil
+	}
+	store, teamID := m.cache, m.selectedTeam.ID
+	snapshot := &cache.TeamIssues{
+		SyncedAt:    m.syncedAt,
+		SnapshotAt:  m.snapshotAt,
+		MyIssues:    slices.Clone(m.list.MyIssues()),
+		MyPageInfo:  m.list.MyPageInfo(),
+		AllIssues:   slices.Clone(m.list.AllIssues()),
+		AllPageInfo: m.list.AllPageInfo(),
+	}
+	return func() tea.Msg {
+		_ = store.SaveTeamIssues(teamID, snapshot)
+		return nil
+	}
+}
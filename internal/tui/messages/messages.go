@@ -1,7 +1,13 @@
 package messages
 
 import (
+	"time"
+
+	"linc/internal/git"
+	"linc/internal/journal"
 	"linc/internal/linear"
+	"linc/internal/session"
+	"linc/internal/webhook"
 )
 
 // View switching messages
@@ -19,29 +25,72 @@ type NextIssueMsg struct{}
 type PrevIssueMsg struct{}
 
 // Data loading messages
+//
+// Load results carry the generation of the request that produced them so
+// results from a previous team or workspace can be discarded.
 type TeamsLoadedMsg struct {
 	Teams []linear.Team
 	Err   error
 }
 
-type IssuesLoadedMsg struct {
-	Issues []linear.Issue
-	Err    error
+// TeamLoadedMsg carries a team's states and the first pages of its open
+// issues and of my issues
+type TeamLoadedMsg struct {
+	States     []linear.State
+	Issues     []linear.Issue
+	PageInfo   linear.PageInfo
+	MyIssues   []linear.Issue
+	MyPageInfo linear.PageInfo
+	SyncedAt   string // when the fetch started, for later incremental refreshes
+	Err        error
+	Generation int
 }
 
-type AllIssuesLoadedMsg struct {
-	Issues []linear.Issue
-	Err    error
+// UpdatedIssuesLoadedMsg carries the team's states and the issues changed
+// since the last sync
+type UpdatedIssuesLoadedMsg struct {
+	States     []linear.State
+	Issues     []linear.Issue
+	SyncedAt   string
+	Err        error
+	Generation int
 }
 
-type StatesLoadedMsg struct {
-	States []linear.State
-	Err    error
+// LoadMoreIssuesMsg requests the next page of team issues, or of my issues
+// if Mine is set, after the given cursor
+type LoadMoreIssuesMsg struct {
+	After string
+	Mine  bool
+}
+
+type MoreIssuesLoadedMsg struct {
+	Issues     []linear.Issue
+	PageInfo   linear.PageInfo
+	Mine       bool
+	Err        error
+	Generation int
 }
 
 type ViewerLoadedMsg struct {
-	Viewer *linear.ViewerResponse
-	Err    error
+	Viewer     *linear.ViewerResponse
+	Err        error
+	Generation int
+}
+
+// SessionsLoadedMsg carries the agent sessions recorded for an issue
+type SessionsLoadedMsg struct {
+	IssueID  string
+	Sessions []session.Session
+}
+
+// WorkingTreeLoadedMsg carries the branch and uncommitted state of the
+// current working tree, the branch to work on the issue in and where it
+// would start
+type WorkingTreeLoadedMsg struct {
+	Branch      string
+	Status      *git.Status
+	IssueBranch string // the issue's branch, Linear's if only that one exists yet
+	Base        string // ref a new issue branch starts from, empty if the branch exists
 }
 
 // Action messages
@@ -53,6 +102,7 @@ type TeamSelectedMsg struct {
 type CommentCreatedMsg struct {
 	Comment *linear.Comment
 	Err     error
+	Queued  bool // saved to the offline journal instead of sent
 }
 
 type StateUpdatedMsg struct {
@@ -63,7 +113,9 @@ type IssueTitleUpdatedMsg struct {
 	IssueID   string
 	NewTitle  string
 	Err       error
-	Completed bool // true when API call is done
+	Completed bool   // true when API call is done
+	Queued    bool   // saved to the offline journal instead of sent
+	UpdatedAt string // the issue's updatedAt after the change, empty if unknown
 }
 
 type IssuePriorityUpdatedMsg struct {
@@ -71,6 +123,8 @@ type IssuePriorityUpdatedMsg struct {
 	NewPriority int
 	Err         error
 	Completed   bool
+	Queued      bool
+	UpdatedAt   string // the issue's updatedAt after the change, empty if unknown
 }
 
 type IssueStateUpdatedMsg struct {
@@ -78,6 +132,8 @@ type IssueStateUpdatedMsg struct {
 	NewStateID string
 	Err        error
 	Completed  bool
+	Queued     bool
+	UpdatedAt  string // the issue's updatedAt after the change, empty if unknown
 }
 
 type CancelIssueMsg struct {
@@ -96,12 +152,35 @@ type OpenBrowserMsg struct {
 
 type StartClaudeMsg struct {
 	Issue        linear.Issue
+	LinearBranch string // Linear's suggested branch, used if only it exists once fetched
 	Comment      string
 	UseBranch    bool
+	Worktree     bool // work on the branch in a separate git worktree
+	Stash        bool // stash local changes before switching to the branch
+	FromHead     bool // create a missing branch from HEAD instead of the base branch
 	PlanMode     bool
+	Resume       bool // resume the previous agent session instead of starting fresh
 	CheckoutOnly bool
 }
 
+// FollowUpMsg applies the follow-ups chosen after a supervised agent session
+type FollowUpMsg struct {
+	Issue        linear.Issue
+	Summary      string // comment to post, empty to post none
+	MoveToReview bool
+	Push         string // branch to push, empty to push none
+}
+
+type BranchPushedMsg struct {
+	Branch string
+	Err    error
+}
+
+// RetryingMsg reports that a Linear request failed and is being retried
+type RetryingMsg struct {
+	Event linear.RetryEvent
+}
+
 type ErrorMsg struct {
 	Err error
 }
@@ -111,3 +190,25 @@ type SettingsSavedMsg struct {
 }
 
 type QuitMsg struct{}
+
+// ReconnectMsg asks to check whether Linear is reachable again and replay
+// changes queued while offline
+type ReconnectMsg struct{}
+
+// JournalReplayedMsg reports the outcome of replaying queued offline changes
+type JournalReplayedMsg struct {
+	Result journal.ReplayResult
+}
+
+// SyncTickMsg triggers a background poll for issues changed elsewhere
+type SyncTickMsg struct{}
+
+// WebhookEventMsg carries a Linear webhook received by the local listener
+type WebhookEventMsg struct {
+	Event webhook.Event
+}
+
+// ExpireHighlightsMsg clears highlights of rows changed by a sync
+type ExpireHighlightsMsg struct {
+	Time time.Time
+}
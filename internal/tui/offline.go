@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"linc/internal/journal"
+	"linc/internal/linear"
+	"linc/internal/tui/messages"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reconnectInterval is how often linc checks whether Linear is reachable
+// again while offline
+const reconnectInterval = 30 * time.Second
+
+// newMutation describes a change to an issue for the offline journal,
+// remembering the issue's updatedAt to detect conflicts on replay
+func (m RootModel) newMutation(kind journal.Kind, issueID string) journal.Mutation {
+	mutation := journal.Mutation{Kind: kind, IssueID: issueID}
+	if issue, ok := m.list.Issue(issueID); ok {
+		mutation.Identifier = issue.Identifier
+		mutation.BaseUpdatedAt = issue.UpdatedAt
+	}
+	return mutation
+}
+
+// sendOrQueue returns a function that runs send, or queues the mutation in
+// the journal if Linear can't be reached. While offline, or while earlier
+// changes are still queued, mutations are queued right away so they are
+// replayed in order.
+func (m RootModel) sendOrQueue(mutation journal.Mutation, send func(context.Context) error) func() (bool, error) {
+	j := m.journal
+	queueNow := j != nil && (m.offline || j.Len() > 0)
+	return func() (bool, error) {
+		if !queueNow {
+			err := send(context.Background())
+			if j == nil || !linear.IsNetworkError(err) {
+				return false, err
+			}
+		}
+		if err := j.Append(mutation); err != nil {
+			return false, fmt.Errorf("failed to queue change while offline: %w", err)
+		}
+		return true, nil
+	}
+}
+
+// mutationDone saves the list after an issue changed, going offline if the
+// change had to be queued
+func (m RootModel) mutationDone(queued bool) (RootModel, tea.Cmd) {
+	save := m.saveTeamIssues()
+	if !queued {
+		return m, save
+	}
+	m, cmd := m.goOffline()
+	return m, tea.Batch(save, cmd)
+}
+
+func (m RootModel) pendingChanges() int {
+	if m.journal == nil {
+		return 0
+	}
+	return m.journal.Len()
+}
+
+// goOffline marks Linear as unreachable and schedules a reconnect attempt
+func (m RootModel) goOffline() (RootModel, tea.Cmd) {
+	m.offline = true
+	if m.reconnectScheduled {
+		return m, nil
+	}
+	m.reconnectScheduled = true
+	return m, tea.Tick(reconnectInterval, func(time.Time) tea.Msg {
+		return messages.ReconnectMsg{}
+	})
+}
+
+// reconnect replays queued changes, or just refreshes if there are none;
+// either fails fast and goes offline again if Linear is still unreachable
+func (m RootModel) reconnect() (RootModel, tea.Cmd) {
+	m.reconnectScheduled = false
+	if m.pendingChanges() > 0 {
+		return m.replayJournal()
+	}
+	return m, m.loadViewer()
+}
+
+func (m RootModel) replayJournal() (RootModel, tea.Cmd) {
+	if m.replaying || m.pendingChanges() == 0 {
+		return m, nil
+	}
+	m.replaying = true
+	j, client := m.journal, m.client
+	return m, func() tea.Msg {
+		return messages.JournalReplayedMsg{Result: j.Replay(context.Background(), client)}
+	}
+}
+
+func (m RootModel) handleReplay(result journal.ReplayResult) (RootModel, tea.Cmd) {
+	m.replaying = false
+
+	if result.Err != nil {
+		if linear.IsAuthError(result.Err) {
+			m.err = result.Err
+			return m, nil
+		}
+		m = m.setTransientStatus("Could not sync offline changes: " + result.Err.Error())
+		return m.goOffline()
+	}
+
+	m.offline = false
+	var notes []string
+	if result.Applied > 0 {
+		notes = append(notes, fmt.Sprintf("Synced %d offline change(s)", result.Applied))
+	}
+	if len(result.Conflicts) > 0 {
+		var changes []string
+		for _, mutation := range result.Conflicts {
+			changes = append(changes, mutation.String())
+		}
+		notes = append(notes, "Discarded, changed on Linear in the meantime: "+strings.Join(changes, ", "))
+	}
+	if len(result.Failures) > 0 {
+		var changes []string
+		for _, failure := range result.Failures {
+			changes = append(changes, fmt.Sprintf("%s (%v)", failure.Mutation, failure.Err))
+		}
+		notes = append(notes, "Rejected by Linear: "+strings.Join(changes, ", "))
+	}
+
+	// Discarded changes were applied locally, so fetch the issues' actual state
+	if len(result.Conflicts) == 0 && len(result.Failures) == 0 {
+		m = m.setTransientStatus(strings.Join(notes, "; "))
+		return m, nil
+	}
+	m.list = m.list.SetStatus(strings.Join(notes, "; "))
+	m.statusTransient = false
+	if m.selectedTeam == nil || !m.viewerFresh {
+		return m, nil
+	}
+	return m.refreshTeam(m.selectedTeam.ID)
+}
+
+// setTransientStatus shows a status that is cleared by the next successful load
+func (m RootModel) setTransientStatus(status string) RootModel {
+	m.list = m.list.SetStatus(status)
+	m.statusTransient = true
+	return m
+}
+
+func (m RootModel) clearStatus() RootModel {
+	if m.statusTransient {
+		m.list = m.list.SetStatus("")
+		m.statusTransient = false
+	}
+	return m
+}
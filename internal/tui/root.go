@@ -1,13 +1,20 @@
 package tui
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
 
+	"linc/internal/branch"
+	"linc/internal/cache"
 	"linc/internal/config"
 	"linc/internal/git"
+	"linc/internal/journal"
 	"linc/internal/linear"
+	"linc/internal/provider"
+	"linc/internal/session"
 	"linc/internal/tui/messages"
 	"linc/internal/tui/styles"
 	"linc/internal/tui/views"
@@ -26,6 +33,7 @@ const (
 	ViewDetail
 	ViewStartWork
 	ViewSettings
+	ViewFollowUp
 )
 
 type RootModel struct {
@@ -34,7 +42,7 @@ type RootModel struct {
 	workspace       *config.Workspace
 	workspaces      []config.Workspace
 	currentDir      string
-	providers       []string
+	providers       []provider.Info
 	currentView     View
 	workspaceSelect views.WorkspaceSelectModel
 	teamSelect      views.TeamSelectModel
@@ -42,24 +50,45 @@ type RootModel struct {
 	detail          views.DetailModel
 	startWork       views.StartWorkModel
 	settings        views.SettingsModel
+	followUp        views.FollowUpModel
 	teams           []linear.Team
 	selectedTeam    *linear.Team
 	err             error
 	quitting        bool
 	startClaude     *messages.StartClaudeMsg
 	addNewWorkspace bool
+	reauthenticate  bool
+
+	// Agent sessions recorded on issues, shown in the detail view
+	sessions     *session.Store
+	repoRoot     string // main working tree of the current repository, empty outside one
+	worktreeRoot string // where issue worktrees of the current repository live
+
+	// Loads belong to a generation; switching team or workspace cancels the
+	// in-flight loads and discards any results tagged with an older generation
+	loadCtx    context.Context
+	cancelLoad context.CancelFunc
+	generation int
+
+	// Cached data is rendered at startup while the viewer and the selected
+	// team are revalidated against Linear
+	cache       *cache.Store
+	viewerID    string
+	viewerFresh bool
+	syncedAt    string // when the selected team's issues were last fetched
+	snapshotAt  string // when they were last fetched in full rather than patched
+
+	// Changes made while Linear is unreachable are queued in the journal
+	// and replayed once it is reachable again
+	journal            *journal.Journal
+	offline            bool
+	replaying          bool
+	reconnectScheduled bool
+	statusTransient    bool // list status is a retry or connection notice
 }
 
-func NewRootModel(client *linear.Client, cfg *config.Config, workspace *config.Workspace, workspaces []config.Workspace, currentDir string, providers []string) RootModel {
-	list := views.NewListModel()
-	if branch := git.GetCurrentBranch(); branch != "" {
-		list = list.SetCurrentBranch(branch)
-	}
-	if wd, err := os.Getwd(); err == nil {
-		list = list.SetWorkingDir(wd)
-	}
-	list = list.SetVersion(Version)
-	return RootModel{
+func NewRootModel(client *linear.Client, cfg *config.Config, workspace *config.Workspace, workspaces []config.Workspace, currentDir string, providers []provider.Info) RootModel {
+	m := RootModel{
 		client:          client,
 		cfg:             cfg,
 		workspace:       workspace,
@@ -68,93 +97,284 @@ func NewRootModel(client *linear.Client, cfg *config.Config, workspace *config.W
 		providers:       providers,
 		workspaceSelect: views.NewIntegratedWorkspaceSelectModel(workspaces),
 		teamSelect:      views.NewTeamSelectModel(),
-		list:            list,
+		repoRoot:        git.GetMainRepoRoot(),
+	}
+	m.sessions, _ = session.Open()
+	if m.repoRoot != "" {
+		m.worktreeRoot, _ = cfg.GetWorktreeRoot(m.repoRoot)
+	}
+	m.list = m.newListModel()
+	m = m.startLoad()
+	return m.openCache()
+}
+
+// WithFollowUp opens the TUI on the follow-ups for a finished supervised
+// agent session, see views.FollowUpModel
+func (m RootModel) WithFollowUp(issue linear.Issue, branch, summary string, sessionErr error) RootModel {
+	m.followUp = views.NewFollowUpModel(issue, branch, summary, sessionErr)
+	m.currentView = ViewFollowUp
+	return m
+}
+
+func (m RootModel) newListModel() views.ListModel {
+	list := views.NewListModel().SetBranchNamer(m.branchNamer())
+	if branch := git.GetCurrentBranch(); branch != "" {
+		list = list.SetCurrentBranch(branch)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		list = list.SetWorkingDir(wd)
+	}
+	return list.SetVersion(Version)
+}
+
+// branchNamer names issue branches for the current workspace and
+// repository; an invalid template is reported at startup and ignored here
+func (m RootModel) branchNamer() *branch.Namer {
+	var workspaceID string
+	if m.workspace != nil {
+		workspaceID = m.workspace.ID
 	}
+	namer, _ := branch.New(m.cfg, workspaceID, m.repoRoot)
+	return namer
+}
+
+// startLoad cancels any in-flight loads and begins a new load generation
+func (m RootModel) startLoad() RootModel {
+	if m.cancelLoad != nil {
+		m.cancelLoad()
+	}
+	m.loadCtx, m.cancelLoad = context.WithCancel(context.Background())
+	m.generation++
+	return m
 }
 
 func (m RootModel) Init() tea.Cmd {
-	return m.loadViewer
+	return tea.Batch(m.loadViewer(), m.scheduleSync())
 }
 
-func (m RootModel) loadViewer() tea.Msg {
-	viewer, err := m.client.GetViewer()
-	if err != nil {
-		return messages.ErrorMsg{Err: err}
+func (m RootModel) loadViewer() tea.Cmd {
+	ctx, gen := m.loadCtx, m.generation
+	return func() tea.Msg {
+		viewer, err := m.client.GetViewer(ctx)
+		return messages.ViewerLoadedMsg{Viewer: viewer, Err: err, Generation: gen}
 	}
-	return messages.ViewerLoadedMsg{Viewer: viewer}
 }
 
-func (m RootModel) loadStates(teamID string) tea.Cmd {
+// loadTeam fetches the team's states and the first pages of its issues and
+// of my issues in one request
+func (m RootModel) loadTeam(teamID string) tea.Cmd {
+	ctx, gen := m.loadCtx, m.generation
 	return func() tea.Msg {
-		states, err := m.client.GetTeamStates(teamID)
-		return messages.StatesLoadedMsg{States: states, Err: err}
+		syncedAt := syncTimestamp()
+		team, err := m.client.GetTeamSnapshot(ctx, teamID)
+		if err != nil {
+			return messages.TeamLoadedMsg{Err: err, Generation: gen}
+		}
+		return messages.TeamLoadedMsg{
+			States:     team.States,
+			Issues:     team.Issues.Issues,
+			PageInfo:   team.Issues.PageInfo,
+			MyIssues:   team.MyIssues.Issues,
+			MyPageInfo: team.MyIssues.PageInfo,
+			SyncedAt:   syncedAt,
+			Generation: gen,
+		}
 	}
 }
 
-func (m RootModel) loadIssues(teamID string) tea.Cmd {
+// loadTeamUpdates fetches the team's states and the issues changed since
+// the last sync
+func (m RootModel) loadTeamUpdates(teamID, since string) tea.Cmd {
+	ctx, gen := m.loadCtx, m.generation
 	return func() tea.Msg {
-		issues, err := m.client.GetAssignedIssues(teamID)
-		return messages.IssuesLoadedMsg{Issues: issues, Err: err}
+		syncedAt := syncTimestamp()
+		updates, err := m.client.GetTeamUpdates(ctx, teamID, since)
+		if err != nil {
+			return messages.UpdatedIssuesLoadedMsg{Err: err, Generation: gen}
+		}
+		return messages.UpdatedIssuesLoadedMsg{States: updates.States, Issues: updates.Issues, SyncedAt: syncedAt, Generation: gen}
 	}
 }
 
-func (m RootModel) loadAllIssues(teamID string) tea.Cmd {
+// loadMoreIssues fetches the next page of the team's issues, or of my
+// issues if mine is set
+func (m RootModel) loadMoreIssues(teamID, after string, mine bool) tea.Cmd {
+	ctx, gen := m.loadCtx, m.generation
 	return func() tea.Msg {
-		issues, err := m.client.GetAllTeamIssues(teamID)
-		return messages.AllIssuesLoadedMsg{Issues: issues, Err: err}
+		fetch := m.client.GetAllTeamIssuesPage
+		if mine {
+			fetch = m.client.GetAssignedIssuesPage
+		}
+		page, err := fetch(ctx, teamID, after)
+		if err != nil {
+			return messages.MoreIssuesLoadedMsg{Mine: mine, Err: err, Generation: gen}
+		}
+		return messages.MoreIssuesLoadedMsg{Issues: page.Issues, PageInfo: page.PageInfo, Mine: mine, Generation: gen}
 	}
 }
 
 func (m RootModel) createComment(issueID, body string) tea.Cmd {
+	mutation := m.newMutation(journal.KindComment, issueID)
+	mutation.Body = body
+	var comment *linear.Comment
+	send := m.sendOrQueue(mutation, func(ctx context.Context) (err error) {
+		comment, err = m.client.CreateComment(ctx, issueID, body)
+		return err
+	})
 	return func() tea.Msg {
-		comment, err := m.client.CreateComment(issueID, body)
-		return messages.CommentCreatedMsg{Comment: comment, Err: err}
+		queued, err := send()
+		return messages.CommentCreatedMsg{Comment: comment, Err: err, Queued: queued}
 	}
 }
 
 func (m RootModel) updateIssueTitle(issueID, title string) tea.Cmd {
+	mutation := m.newMutation(journal.KindTitle, issueID)
+	mutation.Title = title
+	var updatedAt string
+	send := m.sendOrQueue(mutation, func(ctx context.Context) (err error) {
+		updatedAt, err = m.client.UpdateIssueTitle(ctx, issueID, title)
+		return err
+	})
 	return func() tea.Msg {
-		err := m.client.UpdateIssueTitle(issueID, title)
-		return messages.IssueTitleUpdatedMsg{IssueID: issueID, NewTitle: title, Err: err, Completed: true}
+		queued, err := send()
+		return messages.IssueTitleUpdatedMsg{IssueID: issueID, NewTitle: title, Err: err, Completed: true, Queued: queued, UpdatedAt: updatedAt}
 	}
 }
 
 func (m RootModel) updateIssuePriority(issueID string, priority int) tea.Cmd {
+	mutation := m.newMutation(journal.KindPriority, issueID)
+	mutation.Priority = priority
+	var updatedAt string
+	send := m.sendOrQueue(mutation, func(ctx context.Context) (err error) {
+		updatedAt, err = m.client.UpdateIssuePriority(ctx, issueID, priority)
+		return err
+	})
 	return func() tea.Msg {
-		err := m.client.UpdateIssuePriority(issueID, priority)
-		return messages.IssuePriorityUpdatedMsg{IssueID: issueID, NewPriority: priority, Err: err, Completed: true}
+		queued, err := send()
+		return messages.IssuePriorityUpdatedMsg{IssueID: issueID, NewPriority: priority, Err: err, Completed: true, Queued: queued, UpdatedAt: updatedAt}
 	}
 }
 
 func (m RootModel) updateIssueStateByID(issueID, stateID string) tea.Cmd {
+	mutation := m.newMutation(journal.KindState, issueID)
+	mutation.StateID = stateID
+	var updatedAt string
+	send := m.sendOrQueue(mutation, func(ctx context.Context) (err error) {
+		updatedAt, err = m.client.UpdateIssueState(ctx, issueID, stateID)
+		return err
+	})
 	return func() tea.Msg {
-		err := m.client.UpdateIssueState(issueID, stateID)
-		return messages.IssueStateUpdatedMsg{IssueID: issueID, NewStateID: stateID, Err: err, Completed: true}
+		queued, err := send()
+		return messages.IssueStateUpdatedMsg{IssueID: issueID, NewStateID: stateID, Err: err, Completed: true, Queued: queued, UpdatedAt: updatedAt}
 	}
 }
 
 func (m RootModel) cancelIssue(issueID, teamID string) tea.Cmd {
 	return func() tea.Msg {
-		stateID, err := m.client.GetCanceledStateID(teamID)
+		stateID, err := m.client.GetCanceledStateID(context.Background(), teamID)
+		if err != nil {
+			return messages.IssueStateUpdatedMsg{IssueID: issueID, Err: err, Completed: true}
+		}
+		if stateID == "" {
+			return messages.IssueStateUpdatedMsg{IssueID: issueID, Err: nil, Completed: true}
+		}
+		updatedAt, err := m.client.UpdateIssueState(context.Background(), issueID, stateID)
+		return messages.IssueStateUpdatedMsg{IssueID: issueID, NewStateID: stateID, Err: err, Completed: true, UpdatedAt: updatedAt}
+	}
+}
+
+func (m RootModel) moveToReview(issueID, teamID string) tea.Cmd {
+	return func() tea.Msg {
+		stateID, err := m.client.GetInReviewStateID(context.Background(), teamID)
 		if err != nil {
 			return messages.IssueStateUpdatedMsg{IssueID: issueID, Err: err, Completed: true}
 		}
 		if stateID == "" {
 			return messages.IssueStateUpdatedMsg{IssueID: issueID, Err: nil, Completed: true}
 		}
-		err = m.client.UpdateIssueState(issueID, stateID)
-		return messages.IssueStateUpdatedMsg{IssueID: issueID, NewStateID: stateID, Err: err, Completed: true}
+		updatedAt, err := m.client.UpdateIssueState(context.Background(), issueID, stateID)
+		return messages.IssueStateUpdatedMsg{IssueID: issueID, NewStateID: stateID, Err: err, Completed: true, UpdatedAt: updatedAt}
+	}
+}
+
+// providerCanResume reports whether the configured provider can resume
+// agent sessions
+func (m RootModel) providerCanResume() bool {
+	for _, info := range m.providers {
+		if info.ID == m.cfg.GetProvider() {
+			return info.Capabilities.Resume
+		}
+	}
+	return false
+}
+
+// checkProvider reports why the configured provider can't start work as
+// asked, before leaving the TUI
+func (m RootModel) checkProvider(msg messages.StartClaudeMsg) error {
+	id := m.cfg.GetProvider()
+	for _, info := range m.providers {
+		if info.ID == id {
+			return info.Check(provider.Request{PlanMode: msg.PlanMode, Resume: msg.Resume})
+		}
+	}
+	return fmt.Errorf("provider not found: %s", id)
+}
+
+// loadSessions reads the agent sessions recorded for an issue
+func (m RootModel) loadSessions(issueID string) tea.Cmd {
+	if m.sessions == nil {
+		return nil
+	}
+	store := m.sessions
+	return func() tea.Msg {
+		sessions, _ := store.ForIssue(issueID)
+		return messages.SessionsLoadedMsg{IssueID: issueID, Sessions: sessions}
+	}
+}
+
+// loadWorkingTree reads the branch and uncommitted state of the current
+// working tree, which starting work may have to switch away from, picks the
+// issue's branch between the templated one and Linear's, and finds where it
+// would start if it doesn't exist yet
+func (m RootModel) loadWorkingTree(issueBranch, linearBranch string) tea.Cmd {
+	cfg := m.cfg
+	return func() tea.Msg {
+		repo, err := git.Current()
+		if err != nil {
+			return messages.WorkingTreeLoadedMsg{IssueBranch: issueBranch}
+		}
+		status, _ := repo.Status()
+		msg := messages.WorkingTreeLoadedMsg{Branch: repo.Branch(), Status: status}
+
+		remote := repo.Remote()
+		exists := func(branch string) bool { return repo.HasBranch(remote, branch) }
+		msg.IssueBranch = branch.Prefer(issueBranch, linearBranch, exists)
+		if msg.IssueBranch != "" && !exists(msg.IssueBranch) {
+			repoRoot, _ := repo.MainRoot()
+			msg.Base, _ = repo.BaseRef(remote, cfg.BaseBranchFor(repoRoot))
+		}
+		return msg
+	}
+}
+
+func pushBranch(branch string) tea.Cmd {
+	return func() tea.Msg {
+		repo, err := git.Current()
+		if err == nil {
+			err = repo.Push(branch)
+		}
+		return messages.BranchPushedMsg{Branch: branch, Err: err}
 	}
 }
 
 func (m RootModel) markDuplicate(issueID, teamID string) tea.Cmd {
 	return func() tea.Msg {
-		stateID, err := m.client.GetDuplicateStateID(teamID)
+		stateID, err := m.client.GetDuplicateStateID(context.Background(), teamID)
 		if err != nil {
 			return messages.IssueStateUpdatedMsg{IssueID: issueID, Err: err, Completed: true}
 		}
 		if stateID == "" {
-			stateID, err = m.client.GetCanceledStateID(teamID)
+			stateID, err = m.client.GetCanceledStateID(context.Background(), teamID)
 			if err != nil {
 				return messages.IssueStateUpdatedMsg{IssueID: issueID, Err: err, Completed: true}
 			}
@@ -162,95 +382,147 @@ func (m RootModel) markDuplicate(issueID, teamID string) tea.Cmd {
 		if stateID == "" {
 			return messages.IssueStateUpdatedMsg{IssueID: issueID, Err: nil, Completed: true}
 		}
-		err = m.client.UpdateIssueState(issueID, stateID)
-		return messages.IssueStateUpdatedMsg{IssueID: issueID, NewStateID: stateID, Err: err, Completed: true}
+		updatedAt, err := m.client.UpdateIssueState(context.Background(), issueID, stateID)
+		return messages.IssueStateUpdatedMsg{IssueID: issueID, NewStateID: stateID, Err: err, Completed: true, UpdatedAt: updatedAt}
 	}
 }
 
 func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" || (msg.String() == "q" && m.currentView != ViewStartWork && m.currentView != ViewSettings) {
+		if msg.String() == "ctrl+c" || (msg.String() == "q" && m.currentView != ViewStartWork && m.currentView != ViewSettings && m.currentView != ViewFollowUp) {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		if msg.String() == "r" && linear.IsAuthError(m.err) {
+			m.reauthenticate = true
 			m.quitting = true
 			return m, tea.Quit
 		}
 
 	case messages.ViewerLoadedMsg:
-		if msg.Err != nil {
-			m.err = msg.Err
+		if msg.Generation != m.generation {
 			return m, nil
 		}
-
-		m.teams = msg.Viewer.Viewer.Teams.Nodes
-
-		if m.workspace != nil && m.workspace.DefaultTeamID != "" {
-			for _, team := range m.teams {
-				if team.ID == m.workspace.DefaultTeamID {
-					m.selectedTeam = &team
-					m.currentView = ViewList
-					return m, tea.Batch(
-						m.loadStates(team.ID),
-						m.loadIssues(team.ID),
-						m.loadAllIssues(team.ID),
-					)
+		if msg.Err != nil {
+			// Keep showing cached data when the refresh fails for other reasons
+			if m.viewerID == "" || linear.IsAuthError(msg.Err) {
+				m.err = msg.Err
+			} else {
+				m.list = m.list.SetRefreshing(false)
+				m = m.setTransientStatus("Could not refresh: " + msg.Err.Error())
+				if linear.IsNetworkError(msg.Err) {
+					return m.goOffline()
 				}
 			}
+			return m, nil
 		}
 
-		if len(m.teams) == 1 {
-			m.selectedTeam = &m.teams[0]
-			m.currentView = ViewList
-			return m, tea.Batch(
-				m.loadStates(m.teams[0].ID),
-				m.loadIssues(m.teams[0].ID),
-				m.loadAllIssues(m.teams[0].ID),
-			)
-		}
-
-		m.currentView = ViewTeamSelect
-		m.teamSelect = m.teamSelect.SetTeams(m.teams)
-		return m, nil
+		m.viewerFresh = true
+		m.offline = false
+		m = m.clearStatus()
+		var cmd, replay tea.Cmd
+		m, cmd = m.applyViewer(msg.Viewer, true)
+		m, replay = m.replayJournal()
+		return m, tea.Batch(cmd, m.saveViewer(msg.Viewer), replay)
 
 	case messages.TeamSelectedMsg:
-		m.selectedTeam = &msg.Team
+		m = m.startLoad()
 		if msg.SetAsDefault && m.workspace != nil {
 			_ = m.cfg.SetDefaultTeam(m.workspace.ID, msg.Team.ID)
 		}
-		m.currentView = ViewList
-		return m, tea.Batch(
-			m.loadStates(msg.Team.ID),
-			m.loadIssues(msg.Team.ID),
-			m.loadAllIssues(msg.Team.ID),
-		)
+		var cmd tea.Cmd
+		m, cmd = m.selectTeam(msg.Team, m.viewerFresh)
+		if !m.viewerFresh {
+			// Selected from cached teams; the viewer load was just cancelled,
+			// so restart it to revalidate once it returns
+			cmd = m.loadViewer()
+		}
+		return m, cmd
 
-	case messages.StatesLoadedMsg:
+	case messages.TeamLoadedMsg:
+		if msg.Generation != m.generation {
+			return m, nil
+		}
+		m = m.clearStatus()
+		m.list = m.list.SetRefreshing(false)
 		if msg.Err != nil {
-			m.list = m.list.SetError(msg.Err)
-		} else {
-			m.list = m.list.SetStates(msg.States)
+			// A periodic full sync of issues already shown fails like any sync
+			if m.syncedAt != "" {
+				return m.syncFailed(msg.Err)
+			}
+			m = m.setLoadError(msg.Err)
+			return m, nil
 		}
-		return m, nil
+		m.list = m.list.SetStates(msg.States).
+			SetAllIssues(msg.Issues).SetAllPageInfo(msg.PageInfo).
+			SetMyIssues(msg.MyIssues).SetMyPageInfo(msg.MyPageInfo)
+		m.syncedAt = msg.SyncedAt
+		m.snapshotAt = msg.SyncedAt
+		return m, tea.Batch(m.saveTeamStates(msg.States), m.saveTeamIssues())
+
+	case messages.UpdatedIssuesLoadedMsg:
+		if msg.Generation != m.generation {
+			return m, nil
+		}
+		m = m.clearStatus()
+		m.list = m.list.SetRefreshing(false)
+		if msg.Err != nil {
+			return m.syncFailed(msg.Err)
+		}
+		m.list = m.list.SetStates(msg.States).MergeUpdatedIssues(msg.Issues, m.viewerID)
+		m.syncedAt = msg.SyncedAt
+		return m, tea.Batch(m.saveTeamStates(msg.States), m.saveTeamIssues(), m.expireHighlights())
 
-	case messages.IssuesLoadedMsg:
+	case messages.LoadMoreIssuesMsg:
+		if m.selectedTeam == nil {
+			return m, nil
+		}
+		return m, m.loadMoreIssues(m.selectedTeam.ID, msg.After, msg.Mine)
+
+	case messages.MoreIssuesLoadedMsg:
+		if msg.Generation != m.generation {
+			return m, nil
+		}
+		m = m.clearStatus()
 		if msg.Err != nil {
-			m.list = m.list.SetError(msg.Err)
+			m = m.setLoadError(msg.Err)
+			return m, nil
+		}
+		if msg.Mine {
+			m.list = m.list.AppendMyIssues(msg.Issues, msg.PageInfo)
 		} else {
-			m.list = m.list.SetMyIssues(msg.Issues)
+			m.list = m.list.AppendAllIssues(msg.Issues, msg.PageInfo)
 		}
+		return m, m.saveTeamIssues()
+
+	case messages.SwitchToListMsg:
+		m = m.showList()
 		return m, nil
 
-	case messages.AllIssuesLoadedMsg:
+	case messages.FollowUpMsg:
+		m = m.showList()
+		var cmds []tea.Cmd
+		if msg.Summary != "" {
+			cmds = append(cmds, m.createComment(msg.Issue.ID, msg.Summary))
+		}
+		if msg.MoveToReview {
+			cmds = append(cmds, m.moveToReview(msg.Issue.ID, msg.Issue.Team.ID))
+		}
+		if msg.Push != "" {
+			m = m.setTransientStatus("Pushing " + msg.Push + "...")
+			cmds = append(cmds, pushBranch(msg.Push))
+		}
+		return m, tea.Batch(cmds...)
+
+	case messages.BranchPushedMsg:
 		if msg.Err != nil {
-			m.list = m.list.SetError(msg.Err)
+			m = m.setTransientStatus("Could not push: " + msg.Err.Error())
 		} else {
-			m.list = m.list.SetAllIssues(msg.Issues)
+			m = m.setTransientStatus("Pushed " + msg.Branch)
 		}
 		return m, nil
 
-	case messages.SwitchToListMsg:
-		m.currentView = ViewList
-		return m, nil
-
 	case messages.SwitchToTeamSelectMsg:
 		m.currentView = ViewTeamSelect
 		m.teamSelect = m.teamSelect.SetTeams(m.teams)
@@ -270,19 +542,14 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Workspace != nil && (m.workspace == nil || msg.Workspace.ID != m.workspace.ID) {
 			// Switch to the selected workspace
 			m.workspace = msg.Workspace
-			m.client = linear.NewClient(msg.Workspace.APIKey)
+			m.client = m.client.WithAPIKey(msg.Workspace.APIKey)
+			m = m.startLoad()
 			// Reset list model for new workspace
-			m.list = views.NewListModel()
-			if branch := git.GetCurrentBranch(); branch != "" {
-				m.list = m.list.SetCurrentBranch(branch)
-			}
-			if wd, err := os.Getwd(); err == nil {
-				m.list = m.list.SetWorkingDir(wd)
-			}
-			m.list = m.list.SetVersion(Version)
+			m.list = m.newListModel()
 			m.selectedTeam = nil
 			m.teams = nil
-			return m, m.loadViewer
+			m = m.openCache()
+			return m, m.loadViewer()
 		}
 		m.currentView = ViewTeamSelect
 		m.teamSelect = m.teamSelect.SetTeams(m.teams)
@@ -291,12 +558,13 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.SwitchToDetailMsg:
 		m.detail = views.NewDetailModel(msg.Issue)
 		m.currentView = ViewDetail
-		return m, nil
+		return m, m.loadSessions(msg.Issue.ID)
 
 	case messages.NextIssueMsg:
 		if next := m.list.GetNextIssue(); next != nil {
 			m.list = m.list.MoveCursorNext()
 			m.detail = views.NewDetailModel(*next)
+			return m, m.loadSessions(next.ID)
 		}
 		return m, nil
 
@@ -304,12 +572,45 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if prev := m.list.GetPrevIssue(); prev != nil {
 			m.list = m.list.MoveCursorPrev()
 			m.detail = views.NewDetailModel(*prev)
+			return m, m.loadSessions(prev.ID)
+		}
+		return m, nil
+
+	case messages.SessionsLoadedMsg:
+		if m.detail.Issue().ID == msg.IssueID {
+			m.detail = m.detail.SetSessions(msg.Sessions)
+		}
+		if m.currentView == ViewStartWork && m.startWork.Issue().ID == msg.IssueID {
+			// Sessions may have run here or in the issue's worktree
+			dirs := []string{m.currentDir}
+			if branch := m.startWork.Issue().BranchName; m.worktreeRoot != "" && branch != "" {
+				dirs = append(dirs, git.WorktreeDir(m.worktreeRoot, branch))
+			}
+			var label string
+			if prev := session.Resumable(msg.Sessions, m.cfg.GetProvider(), dirs...); prev != nil {
+				label = prev.Provider + ", " + prev.StartedAt.Local().Format("Jan 2 15:04")
+			}
+			m.startWork = m.startWork.SetResumable(label)
 		}
 		return m, nil
 
 	case messages.SwitchToStartWorkMsg:
-		m.startWork = views.NewStartWorkModel(msg.Issue)
+		// Work happens on the branch named by the configured scheme, unless
+		// only Linear's exists yet
+		issue := msg.Issue
+		issue.BranchName = m.branchNamer().Name(issue)
+		m.startWork = views.NewStartWorkModel(issue).SetLinearBranch(msg.Issue.BranchName)
 		m.currentView = ViewStartWork
+		loadWorkingTree := m.loadWorkingTree(issue.BranchName, msg.Issue.BranchName)
+		if m.providerCanResume() {
+			return m, tea.Batch(loadWorkingTree, m.loadSessions(msg.Issue.ID))
+		}
+		return m, loadWorkingTree
+
+	case messages.WorkingTreeLoadedMsg:
+		if m.currentView == ViewStartWork {
+			m.startWork = m.startWork.SetIssueBranch(msg.IssueBranch).SetWorkingTree(msg.Branch, msg.Status, msg.Base)
+		}
 		return m, nil
 
 	case messages.SwitchToSettingsMsg:
@@ -322,21 +623,47 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case messages.StartClaudeMsg:
-		if msg.Comment != "" {
-			m.startClaude = &msg
-			return m, m.createComment(msg.Issue.ID, msg.Comment)
+		// Report a provider that can't start work as asked right away. The
+		// comment is posted once the agent is about to start.
+		if !msg.CheckoutOnly {
+			if err := m.checkProvider(msg); err != nil {
+				m.startWork = m.startWork.SetError(err)
+				return m, nil
+			}
 		}
 		m.startClaude = &msg
 		m.quitting = true
 		return m, tea.Quit
 
 	case messages.CommentCreatedMsg:
-		if m.startClaude != nil {
-			m.quitting = true
-			return m, tea.Quit
+		if msg.Queued {
+			m.offline = true
+		}
+		if msg.Err != nil {
+			m = m.setTransientStatus("Could not post comment: " + msg.Err.Error())
 		}
 		return m, nil
 
+	case messages.RetryingMsg:
+		m = m.setTransientStatus(msg.Event.String())
+		return m, nil
+
+	case messages.ReconnectMsg:
+		return m.reconnect()
+
+	case messages.SyncTickMsg:
+		return m.poll()
+
+	case messages.WebhookEventMsg:
+		return m.handleWebhook(msg.Event)
+
+	case messages.ExpireHighlightsMsg:
+		m.list = m.list.ExpireHighlights(msg.Time)
+		return m, m.expireHighlights()
+
+	case messages.JournalReplayedMsg:
+		return m.handleReplay(msg.Result)
+
 	case messages.ErrorMsg:
 		m.err = msg.Err
 		return m, nil
@@ -346,21 +673,21 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.updateIssueTitle(msg.IssueID, msg.NewTitle)
 		}
 		m.list, _ = m.list.Update(msg)
-		return m, nil
+		return m.mutationDone(msg.Queued)
 
 	case messages.IssuePriorityUpdatedMsg:
 		if !msg.Completed {
 			return m, m.updateIssuePriority(msg.IssueID, msg.NewPriority)
 		}
 		m.list, _ = m.list.Update(msg)
-		return m, nil
+		return m.mutationDone(msg.Queued)
 
 	case messages.IssueStateUpdatedMsg:
 		if !msg.Completed {
 			return m, m.updateIssueStateByID(msg.IssueID, msg.NewStateID)
 		}
 		m.list, _ = m.list.Update(msg)
-		return m, nil
+		return m.mutationDone(msg.Queued)
 
 	case messages.CancelIssueMsg:
 		return m, m.cancelIssue(msg.IssueID, msg.TeamID)
@@ -385,6 +712,8 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.startWork, cmd = m.startWork.Update(msg)
 	case ViewSettings:
 		m.settings, cmd = m.settings.Update(msg)
+	case ViewFollowUp:
+		m.followUp, cmd = m.followUp.Update(msg)
 	}
 
 	return m, cmd
@@ -395,6 +724,10 @@ func (m RootModel) View() string {
 		return ""
 	}
 
+	if linear.IsAuthError(m.err) {
+		return m.renderAuthError()
+	}
+
 	if m.err != nil {
 		return styles.ErrorStyle.Render(m.err.Error())
 	}
@@ -405,18 +738,53 @@ func (m RootModel) View() string {
 	case ViewTeamSelect:
 		return m.teamSelect.View()
 	case ViewList:
-		return m.list.View()
+		return m.list.SetOffline(m.offline).SetPending(m.pendingChanges()).View()
 	case ViewDetail:
 		return m.detail.View()
 	case ViewStartWork:
 		return m.startWork.View()
 	case ViewSettings:
 		return m.settings.View()
+	case ViewFollowUp:
+		return m.followUp.View()
 	}
 
 	return "Loading..."
 }
 
+func (m RootModel) renderAuthError() string {
+	name := "this workspace"
+	if m.workspace != nil {
+		name = "workspace '" + m.workspace.Name + "'"
+	}
+	return styles.ErrorStyle.Render("Linear rejected the API key for "+name+".") + "\n" +
+		styles.SubtitleStyle.Render("It may have expired or been revoked.") + "\n" +
+		styles.HelpStyle.Render("r: enter a new API key • q: quit")
+}
+
+// showList returns to the issue list, or to team selection if no team has
+// been picked yet
+func (m RootModel) showList() RootModel {
+	if m.selectedTeam == nil && len(m.teams) > 0 {
+		m.currentView = ViewTeamSelect
+		m.teamSelect = m.teamSelect.SetTeams(m.teams)
+		return m
+	}
+	m.currentView = ViewList
+	return m
+}
+
+// setLoadError shows a failed load in the list, except when Linear rejected
+// the API key, which is handled by the root view so the key can be replaced
+func (m RootModel) setLoadError(err error) RootModel {
+	if linear.IsAuthError(err) {
+		m.err = err
+		return m
+	}
+	m.list = m.list.SetError(err)
+	return m
+}
+
 func (m RootModel) ShouldStartClaude() *messages.StartClaudeMsg {
 	return m.startClaude
 }
@@ -425,6 +793,16 @@ func (m RootModel) ShouldAddNewWorkspace() bool {
 	return m.addNewWorkspace
 }
 
+// ShouldReauthenticate reports whether the user asked to replace a rejected API key
+func (m RootModel) ShouldReauthenticate() bool {
+	return m.reauthenticate
+}
+
+// Workspace returns the workspace the TUI was showing when it exited
+func (m RootModel) Workspace() *config.Workspace {
+	return m.workspace
+}
+
 func openBrowser(url string) {
 	var cmd *exec.Cmd
 
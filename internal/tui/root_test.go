@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"linc/internal/config"
+	"linc/internal/linear"
+	"linc/internal/linear/lineartest"
+	"linc/internal/tui/messages"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestModel returns a root model for the fake server's workspace, with
+// linc's cache and journal in a temporary home and background polling off
+func newTestModel(t *testing.T, s *lineartest.Server) RootModel {
+	t.Helper()
+	cfg := &config.Config{Sync: &config.SyncConfig{Interval: -1}}
+	workspace := &config.Workspace{ID: "ws-test", Name: "Acme", APIKey: lineartest.APIKey, DefaultTeamID: "team-eng"}
+	return NewRootModel(s.Client(), cfg, workspace, []config.Workspace{*workspace}, t.TempDir(), nil)
+}
+
+// run feeds the messages produced by cmd, and by the commands those lead
+// to, back into the model until nothing is left to do. Commands still
+// blocked after a while, such as timers, are dropped.
+func run(t *testing.T, m RootModel, cmd tea.Cmd) RootModel {
+	t.Helper()
+	queue := []tea.Cmd{cmd}
+	for steps := 0; len(queue) > 0; steps++ {
+		if steps > 100 {
+			t.Fatal("model kept producing commands")
+		}
+		cmd, queue = queue[0], queue[1:]
+		if cmd == nil {
+			continue
+		}
+
+		done := make(chan tea.Msg, 1)
+		go func() { done <- cmd() }()
+		var msg tea.Msg
+		select {
+		case msg = <-done:
+		case <-time.After(2 * time.Second):
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case nil:
+		case tea.BatchMsg:
+			queue = append(queue, msg...)
+		default:
+			next, cmd := m.Update(msg)
+			m = next.(RootModel)
+			queue = append(queue, cmd)
+		}
+	}
+	return m
+}
+
+func identifiers(issues []linear.Issue) []string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.Identifier
+	}
+	return ids
+}
+
+func TestRootModelLoadAndRename(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+
+	m := newTestModel(t, s)
+	m = run(t, m, m.Init())
+
+	if m.err != nil {
+		t.Fatalf("loading failed: %v", m.err)
+	}
+	if m.currentView != ViewList || m.selectedTeam == nil || m.selectedTeam.ID != "team-eng" {
+		t.Fatalf("showing view %v of team %v, want the list of team-eng", m.currentView, m.selectedTeam)
+	}
+	if got := identifiers(m.list.AllIssues()); len(got) != 5 {
+		t.Errorf("all issues = %v, want the 5 open ones", got)
+	}
+	if got := identifiers(m.list.MyIssues()); len(got) != 3 {
+		t.Errorf("my issues = %v, want the viewer's 3", got)
+	}
+	if m.syncedAt == "" || m.snapshotAt == "" {
+		t.Error("the load wasn't recorded for later syncs")
+	}
+
+	m = run(t, m, func() tea.Msg {
+		return messages.IssueTitleUpdatedMsg{IssueID: "issue-1", NewTitle: "Set up CI pipelines"}
+	})
+
+	var remote linear.Issue
+	s.Update(func(data *lineartest.Dataset) { remote = *data.Issue("ENG-1") })
+	if remote.Title != "Set up CI pipelines" {
+		t.Errorf("title on Linear = %q, want the new one", remote.Title)
+	}
+	if issue, ok := m.list.Issue("issue-1"); !ok || issue.Title != "Set up CI pipelines" {
+		t.Errorf("title in the list = %q, want the new one", issue.Title)
+	}
+	// Changes queued offline later start from our own edit
+	if issue, _ := m.list.Issue("issue-1"); issue.UpdatedAt != remote.UpdatedAt {
+		t.Errorf("updatedAt in the list = %q, want Linear's %q", issue.UpdatedAt, remote.UpdatedAt)
+	}
+	if m.pendingChanges() != 0 {
+		t.Errorf("%d changes queued while online", m.pendingChanges())
+	}
+
+	// The next start shows the renamed issue from the cache before loading
+	cached := newTestModel(t, s)
+	if issue, ok := cached.list.Issue("issue-1"); !ok || issue.Title != "Set up CI pipelines" {
+		t.Errorf("cached title = %q, want the new one", issue.Title)
+	}
+	if !strings.Contains(cached.View(), "ENG-1") {
+		t.Error("cached issues aren't rendered")
+	}
+}
+
+func TestRootModelSyncDropsArchivedIssues(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+
+	m := newTestModel(t, s)
+	m = run(t, m, m.Init())
+	if _, ok := m.list.Issue("issue-2"); !ok {
+		t.Fatal("issue-2 wasn't loaded")
+	}
+
+	s.Update(func(data *lineartest.Dataset) {
+		issue := data.Issue("issue-2")
+		issue.ArchivedAt = time.Now().UTC().Format(time.RFC3339)
+		issue.UpdatedAt = issue.ArchivedAt
+	})
+	m = run(t, m, func() tea.Msg { return messages.SyncTickMsg{} })
+
+	if ops := s.Operations(); ops[len(ops)-1] != "TeamUpdates" {
+		t.Errorf("synced with %s, want only the updates", ops[len(ops)-1])
+	}
+	if _, ok := m.list.Issue("issue-2"); ok {
+		t.Error("archived issue-2 is still listed")
+	}
+	for _, issue := range m.list.MyIssues() {
+		if issue.ID == "issue-2" {
+			t.Error("archived issue-2 is still among my issues")
+		}
+	}
+}
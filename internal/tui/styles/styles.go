@@ -152,11 +152,26 @@ var (
 			Foreground(secondaryColor).
 			MarginTop(1)
 
+	// Unavailable entries in pickers
+	DisabledItemStyle = lipgloss.NewStyle().
+				Foreground(secondaryColor)
+
 	// Error styles
 	ErrorStyle = lipgloss.NewStyle().
 			Foreground(errorColor).
 			Bold(true)
 
+	// Warnings that need a decision before continuing
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(PrimaryColor)
+
+	// Badge shown in the list header while offline changes are queued
+	PendingBadgeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("0")).
+				Background(PrimaryColor).
+				Padding(0, 1).
+				MarginLeft(2)
+
 	// Filter styles
 	FilterPromptStyle = lipgloss.NewStyle().
 				Foreground(PrimaryColor).
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"time"
+
+	"linc/internal/linear"
+	"linc/internal/tui/messages"
+	"linc/internal/tui/views"
+	"linc/internal/webhook"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fullSyncInterval is how often the selected team's issues are fetched in
+// full instead of patched with the changes since the last sync
+const fullSyncInterval = 30 * time.Minute
+
+// scheduleSync schedules the next background poll, unless polling is disabled
+func (m RootModel) scheduleSync() tea.Cmd {
+	interval := m.cfg.GetSyncInterval()
+	if interval <= 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return messages.SyncTickMsg{}
+	})
+}
+
+// canSync reports whether the selected team's issues can be patched with
+// changes since the last sync
+func (m RootModel) canSync() bool {
+	return !m.offline && m.viewerFresh && m.selectedTeam != nil && m.syncedAt != ""
+}
+
+// poll fetches issues changed since the last sync and schedules the next poll.
+// Offline, the reconnect loop takes over instead.
+func (m RootModel) poll() (RootModel, tea.Cmd) {
+	next := m.scheduleSync()
+	if !m.canSync() {
+		return m, next
+	}
+	return m, tea.Batch(m.syncTeam(m.selectedTeam.ID), next)
+}
+
+// syncTeam fetches the issues changed since the last sync. Issues moved to
+// another team or purged from Linear's trash never show up among them, so
+// once fullSyncInterval has passed the first pages are fetched afresh instead.
+func (m RootModel) syncTeam(teamID string) tea.Cmd {
+	snapshotAt, err := time.Parse(time.RFC3339, m.snapshotAt)
+	if err != nil || time.Since(snapshotAt) >= fullSyncInterval {
+		return m.loadTeam(teamID)
+	}
+	return m.loadTeamUpdates(teamID, m.syncedAt)
+}
+
+// syncFailed reports a failed sync; the issues shown so far stay
+func (m RootModel) syncFailed(err error) (RootModel, tea.Cmd) {
+	if linear.IsAuthError(err) {
+		m.err = err
+		return m, nil
+	}
+	m = m.setTransientStatus("Could not refresh: " + err.Error())
+	if linear.IsNetworkError(err) {
+		return m.goOffline()
+	}
+	return m, nil
+}
+
+// handleWebhook applies a webhook for an issue of the selected team: removed
+// issues are dropped right away, other changes trigger a sync
+func (m RootModel) handleWebhook(event webhook.Event) (RootModel, tea.Cmd) {
+	if event.Type != "Issue" || m.selectedTeam == nil {
+		return m, nil
+	}
+	if event.TeamID != "" && event.TeamID != m.selectedTeam.ID {
+		return m, nil
+	}
+
+	if event.Action == "remove" {
+		m.list = m.list.RemoveIssue(event.ID)
+		return m, m.saveTeamIssues()
+	}
+	if !m.canSync() {
+		return m, nil
+	}
+	return m, m.syncTeam(m.selectedTeam.ID)
+}
+
+// expireHighlights schedules clearing the highlights of changed rows
+func (m RootModel) expireHighlights() tea.Cmd {
+	if !m.list.HasHighlights() {
+		return nil
+	}
+	return tea.Tick(views.HighlightDuration, func(t time.Time) tea.Msg {
+		return messages.ExpireHighlightsMsg{Time: t}
+	})
+}
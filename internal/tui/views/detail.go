@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"linc/internal/linear"
+	"linc/internal/session"
 	"linc/internal/tui/messages"
 	"linc/internal/tui/styles"
 
@@ -16,9 +17,13 @@ import (
 
 type DetailModel struct {
 	issue        linear.Issue
-	activeButton int // 0 = Open in Browser, 1 = Start Working
+	activeButton int               // 0 = Open in Browser, 1 = Start Working
+	sessions     []session.Session // agent sessions on the issue, oldest first
 }
 
+// historyLimit is how many recent agent sessions the history pane lists
+const historyLimit = 5
+
 func NewDetailModel(issue linear.Issue) DetailModel {
 	return DetailModel{
 		issue:        issue,
@@ -120,6 +125,11 @@ func (m DetailModel) View() string {
 		s.WriteString(formatMarkdown(m.issue.Description) + "\n")
 	}
 
+	if len(m.sessions) > 0 {
+		s.WriteString("\n" + styles.DetailLabelStyle.Render("History") + "\n")
+		s.WriteString(m.renderHistory() + "\n")
+	}
+
 	// Buttons
 	s.WriteString("\n")
 	openBtn := styles.ButtonStyle.Render("Open in Browser (o)")
@@ -139,6 +149,33 @@ func (m DetailModel) View() string {
 	return s.String()
 }
 
+// renderHistory lists the most recent agent sessions on the issue
+func (m DetailModel) renderHistory() string {
+	var lines []string
+	for i := len(m.sessions) - 1; i >= 0 && len(lines) < historyLimit; i-- {
+		sess := m.sessions[i]
+		parts := []string{sess.StartedAt.Local().Format("Jan 2 15:04"), sess.Provider}
+		if sess.PlanMode {
+			parts = append(parts, "plan")
+		}
+		if sess.Branch != "" {
+			parts = append(parts, sess.Branch)
+		}
+		if d := sess.Duration(); d > 0 {
+			parts = append(parts, d.Round(time.Minute).String())
+		}
+		parts = append(parts, sess.Status())
+		if commits := sess.Commits(); commits != "" {
+			parts = append(parts, commits)
+		}
+		lines = append(lines, "  "+strings.Join(parts, " · "))
+	}
+	if more := len(m.sessions) - historyLimit; more > 0 {
+		lines = append(lines, styles.SubtitleStyle.Render(fmt.Sprintf("  %d earlier, see linc sessions %s", more, m.issue.Identifier)))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m DetailModel) renderHeaderRow() string {
 	prio := m.renderPriority()
 	identifier := styles.IssueIdentifierStyle.Render(m.issue.Identifier)
@@ -243,6 +280,12 @@ func (m DetailModel) priorityLabel(priority int) string {
 	}
 }
 
+// SetSessions sets the agent sessions recorded for the issue
+func (m DetailModel) SetSessions(sessions []session.Session) DetailModel {
+	m.sessions = sessions
+	return m
+}
+
 func (m DetailModel) Issue() linear.Issue {
 	return m.issue
 }
@@ -0,0 +1,163 @@
+package views
+
+import (
+	"strings"
+
+	"linc/internal/linear"
+	"linc/internal/tui/messages"
+	"linc/internal/tui/styles"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FollowUpModel is shown when a supervised agent session ends, offering to
+// wrap up the issue before returning to the list
+type FollowUpModel struct {
+	issue        linear.Issue
+	branch       string
+	sessionErr   error
+	summaryInput textarea.Model
+	postSummary  bool
+	moveToReview bool
+	push         bool
+	focusIndex   int // 0 = summary, 1 = post summary, 2 = move to review, 3 = push, 4 = done button
+}
+
+// NewFollowUpModel creates the follow-up view for a finished session on the
+// given branch, with summary as the draft comment
+func NewFollowUpModel(issue linear.Issue, branch, summary string, sessionErr error) FollowUpModel {
+	ta := textarea.New()
+	ta.SetValue(summary)
+	ta.SetWidth(72)
+	ta.SetHeight(6)
+	ta.ShowLineNumbers = false
+
+	return FollowUpModel{
+		issue:        issue,
+		branch:       branch,
+		sessionErr:   sessionErr,
+		summaryInput: ta,
+		postSummary:  summary != "",
+		focusIndex:   1,
+	}
+}
+
+func (m FollowUpModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m FollowUpModel) Update(msg tea.Msg) (FollowUpModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.focusIndex == 0 {
+			var cmd tea.Cmd
+			m.summaryInput, cmd = m.summaryInput.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "tab":
+		return m.focus((m.focusIndex + 1) % 5)
+	case "shift+tab":
+		return m.focus((m.focusIndex + 4) % 5)
+	case "esc":
+		return m, func() tea.Msg {
+			return messages.SwitchToListMsg{}
+		}
+	}
+
+	// The summary takes all other keys while it is edited
+	if m.focusIndex == 0 {
+		var cmd tea.Cmd
+		m.summaryInput, cmd = m.summaryInput.Update(keyMsg)
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "down", "j":
+		return m.focus((m.focusIndex + 1) % 5)
+	case "up", "k":
+		return m.focus((m.focusIndex + 4) % 5)
+	case "enter", " ":
+		switch m.focusIndex {
+		case 1:
+			m.postSummary = !m.postSummary
+		case 2:
+			m.moveToReview = !m.moveToReview
+		case 3:
+			if m.branch != "" {
+				m.push = !m.push
+			}
+		case 4:
+			return m, m.apply()
+		}
+	}
+	return m, nil
+}
+
+func (m FollowUpModel) focus(index int) (FollowUpModel, tea.Cmd) {
+	m.focusIndex = index
+	if index == 0 {
+		return m, m.summaryInput.Focus()
+	}
+	m.summaryInput.Blur()
+	return m, nil
+}
+
+// apply returns the chosen follow-ups, which also return to the list
+func (m FollowUpModel) apply() tea.Cmd {
+	msg := messages.FollowUpMsg{
+		Issue:        m.issue,
+		MoveToReview: m.moveToReview,
+	}
+	if summary := strings.TrimSpace(m.summaryInput.Value()); m.postSummary && summary != "" {
+		msg.Summary = summary
+	}
+	if m.push {
+		msg.Push = m.branch
+	}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+func (m FollowUpModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(styles.TitleStyle.Render("Finished Working on "+m.issue.Identifier) + "\n\n")
+	s.WriteString(styles.SubtitleStyle.Render(m.issue.Title) + "\n\n")
+	if m.sessionErr != nil {
+		s.WriteString(styles.ErrorStyle.Render("The agent session ended with an error: "+m.sessionErr.Error()) + "\n\n")
+	}
+
+	// Summary comment
+	summaryStyle := styles.InputStyle
+	if m.focusIndex == 0 {
+		summaryStyle = styles.FocusedInputStyle
+	}
+	s.WriteString(styles.DetailLabelStyle.Render("Summary:") + "\n")
+	s.WriteString(summaryStyle.Render(m.summaryInput.View()) + "\n\n")
+
+	s.WriteString(renderCheckbox("Post summary as a comment", m.postSummary, m.focusIndex == 1) + "\n")
+	s.WriteString(renderCheckbox("Move to In Review", m.moveToReview, m.focusIndex == 2) + "\n")
+	if m.branch != "" {
+		s.WriteString(renderCheckbox("Push branch", m.push, m.focusIndex == 3))
+		s.WriteString(styles.SubtitleStyle.Render("  (" + m.branch + ")"))
+	} else {
+		s.WriteString(renderCheckbox("Push branch (not on a branch)", false, m.focusIndex == 3))
+	}
+	s.WriteString("\n\n")
+
+	doneBtnStyle := styles.ButtonStyle
+	if m.focusIndex == 4 {
+		doneBtnStyle = styles.ActiveButtonStyle
+	}
+	s.WriteString(doneBtnStyle.Render("Done") + "\n")
+
+	s.WriteString(styles.HelpStyle.Render("\ntab/arrows: navigate • space/enter: toggle/select • esc: back to list"))
+
+	return s.String()
+}
@@ -3,10 +3,12 @@ package views
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
 
+	"linc/internal/branch"
 	"linc/internal/linear"
 	"linc/internal/tui/messages"
 	"linc/internal/tui/styles"
@@ -25,6 +27,13 @@ const (
 	EditModeStatus
 )
 
+// HighlightDuration is how long rows changed by a background sync stay highlighted
+const HighlightDuration = 10 * time.Second
+
+// loadMoreThreshold is how close the cursor must get to the end of the list
+// before the next page of issues is requested
+const loadMoreThreshold = 5
+
 type ListModel struct {
 	issues        []linear.Issue
 	allIssues     []linear.Issue
@@ -39,16 +48,27 @@ type ListModel struct {
 	loading       bool
 	showAllIssues bool // false = my issues, true = all issues
 	err           error
-	currentBranch string         // current git branch
-	currentIssue  *linear.Issue  // issue matching current branch (if any)
-	workingDir    string         // current working directory
-	version       string         // app version
+	currentBranch string               // current git branch
+	currentIssue  *linear.Issue        // issue matching current branch (if any)
+	branches      *branch.Namer        // names issue branches, to match the current branch
+	workingDir    string               // current working directory
+	version       string               // app version
+	status        string               // transient connection status, e.g. retries
+	refreshing    bool                 // showing cached issues while revalidating
+	offline       bool                 // Linear is unreachable
+	pending       int                  // changes queued while offline
+	highlighted   map[string]time.Time // issue ID -> when a sync changed it
+
+	// Pagination state
+	myPageInfo  linear.PageInfo
+	allPageInfo linear.PageInfo
+	loadingMore bool
 
 	// Edit mode state
-	editMode      EditMode
-	editInput     textinput.Model  // for renaming
-	editCursor    int              // for priority/status selection
-	editIssue     *linear.Issue    // issue being edited
+	editMode   EditMode
+	editInput  textinput.Model // for renaming
+	editCursor int             // for priority/status selection
+	editIssue  *linear.Issue   // issue being edited
 }
 
 func NewListModel() ListModel {
@@ -105,6 +125,27 @@ func (m *ListModel) applyFilter() {
 	}
 }
 
+// maybeLoadMore requests the next page of the active issue list once the
+// cursor gets close to the end of what has been loaded so far
+func (m *ListModel) maybeLoadMore() tea.Cmd {
+	pageInfo := m.myPageInfo
+	if m.showAllIssues {
+		pageInfo = m.allPageInfo
+	}
+	if m.loadingMore || !pageInfo.HasNextPage || pageInfo.EndCursor == "" {
+		return nil
+	}
+	if len(m.filtered)-m.cursor > loadMoreThreshold {
+		return nil
+	}
+
+	m.loadingMore = true
+	after, mine := pageInfo.EndCursor, !m.showAllIssues
+	return func() tea.Msg {
+		return messages.LoadMoreIssuesMsg{After: after, Mine: mine}
+	}
+}
+
 func (m *ListModel) groupIssuesByState() {
 	m.issuesByState = make(map[string][]linear.Issue)
 	for _, issue := range m.issues {
@@ -137,33 +178,12 @@ func (m *ListModel) findDefaultState() {
 
 func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 	switch msg := msg.(type) {
-	case messages.StatesLoadedMsg:
-		if msg.Err != nil {
-			m.err = msg.Err
-			return m, nil
-		}
-		m.states = msg.States
-		m.findDefaultState()
-		m.applyFilter()
-		return m, nil
-
-	case messages.IssuesLoadedMsg:
-		if msg.Err != nil {
-			m.err = msg.Err
-			m.loading = false
-			return m, nil
-		}
-		m.issues = msg.Issues
-		m.loading = false
-		m.groupIssuesByState()
-		m.applyFilter()
-		return m, nil
-
 	case messages.IssueTitleUpdatedMsg:
 		if msg.Err != nil {
 			m.err = msg.Err
 		} else {
 			m.updateIssueTitle(msg.IssueID, msg.NewTitle)
+			m.updateIssueUpdatedAt(msg.IssueID, msg.UpdatedAt)
 		}
 		m.editMode = EditModeNone
 		m.editIssue = nil
@@ -174,6 +194,7 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 			m.err = msg.Err
 		} else {
 			m.updateIssuePriority(msg.IssueID, msg.NewPriority)
+			m.updateIssueUpdatedAt(msg.IssueID, msg.UpdatedAt)
 		}
 		m.editMode = EditModeNone
 		m.editIssue = nil
@@ -184,6 +205,7 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 			m.err = msg.Err
 		} else {
 			m.updateIssueState(msg.IssueID, msg.NewStateID)
+			m.updateIssueUpdatedAt(msg.IssueID, msg.UpdatedAt)
 		}
 		m.editMode = EditModeNone
 		m.editIssue = nil
@@ -223,6 +245,8 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 			if m.cursor < len(m.filtered)-1 {
 				m.cursor++
 			}
+			cmd := m.maybeLoadMore()
+			return m, cmd
 		case "left", "h":
 			if m.activeState > 0 {
 				m.activeState--
@@ -230,6 +254,8 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 				m.filterInput.SetValue("")
 				m.applyFilter()
 			}
+			cmd := m.maybeLoadMore()
+			return m, cmd
 		case "right", "l":
 			if m.activeState < len(m.states)-1 {
 				m.activeState++
@@ -237,6 +263,8 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 				m.filterInput.SetValue("")
 				m.applyFilter()
 			}
+			cmd := m.maybeLoadMore()
+			return m, cmd
 		case "/":
 			m.filtering = true
 			m.filterInput.Focus()
@@ -258,7 +286,8 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 			}
 		case "a":
 			m = m.ToggleShowAll()
-			return m, nil
+			cmd := m.maybeLoadMore()
+			return m, cmd
 		case "R":
 			if len(m.filtered) > 0 {
 				m.editMode = EditModeRename
@@ -505,6 +534,22 @@ func (m *ListModel) updateIssueState(issueID, newStateID string) {
 	m.applyFilter()
 }
 
+// updateIssueUpdatedAt records when Linear last changed the issue, so changes
+// queued offline later aren't taken for conflicts with our own edit
+func (m *ListModel) updateIssueUpdatedAt(issueID, updatedAt string) {
+	if updatedAt == "" {
+		return
+	}
+	for _, issues := range [][]linear.Issue{m.issues, m.myIssues, m.allIssues} {
+		for i := range issues {
+			if issues[i].ID == issueID {
+				issues[i].UpdatedAt = updatedAt
+				break
+			}
+		}
+	}
+}
+
 func (m *ListModel) removeIssue(issueID string) {
 	for i := range m.issues {
 		if m.issues[i].ID == issueID {
@@ -533,7 +578,11 @@ func (m *ListModel) removeIssue(issueID string) {
 
 func (m ListModel) View() string {
 	if m.loading {
-		return m.renderLogo() + "\n\n" + styles.TitleStyle.Render("Loading issues...")
+		view := m.renderLogo() + "\n\n" + styles.TitleStyle.Render("Loading issues...")
+		if m.status != "" {
+			view += "\n" + styles.SubtitleStyle.Render(m.status)
+		}
+		return view
 	}
 
 	if m.err != nil {
@@ -562,7 +611,22 @@ func (m ListModel) View() string {
 	if m.showAllIssues {
 		modeLabel = "All Issues"
 	}
-	s.WriteString(styles.TitleStyle.Render(modeLabel) + "\n\n")
+	title := styles.TitleStyle.Render(modeLabel)
+	if m.refreshing {
+		title = lipgloss.JoinHorizontal(lipgloss.Top, title, styles.SubtitleStyle.Render("  refreshing…"))
+	}
+	if m.offline {
+		title = lipgloss.JoinHorizontal(lipgloss.Top, title, styles.SubtitleStyle.Render("  offline"))
+	}
+	if m.pending > 0 {
+		badge := fmt.Sprintf("%d pending", m.pending)
+		title = lipgloss.JoinHorizontal(lipgloss.Top, title, styles.PendingBadgeStyle.Render(badge))
+	}
+	s.WriteString(title + "\n")
+	if m.status != "" {
+		s.WriteString(styles.SubtitleStyle.Render(m.status) + "\n")
+	}
+	s.WriteString("\n")
 
 	for i, state := range m.states {
 		count := len(m.issuesByState[state.ID])
@@ -621,6 +685,10 @@ func (m ListModel) View() string {
 		}
 	}
 
+	if m.loadingMore {
+		s.WriteString("\n" + styles.SubtitleStyle.Render("  Loading more issues..."))
+	}
+
 	s.WriteString(styles.HelpStyle.Render("\nh/l: status • j/k: navigate • R: rename • p: priority • s: status • a: my/all • /: filter • ,: settings • enter: select • q: quit"))
 
 	return s.String()
@@ -742,6 +810,10 @@ func (m ListModel) renderIssueRow(issue linear.Issue, selected bool, aboveSelect
 	}
 
 	identifierStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	if _, ok := m.highlighted[issue.ID]; ok {
+		// Changed by a background sync
+		identifierStyle = identifierStyle.Foreground(styles.PrimaryColor)
+	}
 	identifier := identifierStyle.Render(issue.Identifier)
 	identifier = padRightStyled(identifier, colIdentifier)
 
@@ -818,6 +890,15 @@ func (m ListModel) renderBranchBox() string {
 		identifier := styles.IssueIdentifierStyle.Render(m.currentIssue.Identifier)
 		title := m.currentIssue.Title
 		content.WriteString(fmt.Sprintf("%s %s %s", stateIcon, identifier, title))
+
+		// Point out branches that don't follow the naming scheme
+		if m.branches.Templated() {
+			if name := m.branches.Name(*m.currentIssue); name != m.currentBranch {
+				content.WriteString("\n")
+				content.WriteString(styles.BranchLabelStyle.Render("scheme: "))
+				content.WriteString(styles.DisabledItemStyle.Render(name))
+			}
+		}
 	}
 
 	return styles.BranchBoxStyle.Render(content.String())
@@ -945,6 +1026,154 @@ func (m ListModel) SetAllIssues(issues []linear.Issue) ListModel {
 	return m
 }
 
+func (m ListModel) SetMyPageInfo(pageInfo linear.PageInfo) ListModel {
+	m.myPageInfo = pageInfo
+	return m
+}
+
+func (m ListModel) SetAllPageInfo(pageInfo linear.PageInfo) ListModel {
+	m.allPageInfo = pageInfo
+	return m
+}
+
+// AppendMyIssues adds a further page of the user's issues
+func (m ListModel) AppendMyIssues(issues []linear.Issue, pageInfo linear.PageInfo) ListModel {
+	m.myIssues = appendNewIssues(m.myIssues, issues)
+	m.myPageInfo = pageInfo
+	m.loadingMore = false
+	if !m.showAllIssues {
+		m.issues = m.myIssues
+		m.groupIssuesByState()
+		m.applyFilter()
+	}
+	m.findCurrentIssue()
+	return m
+}
+
+// AppendAllIssues adds a further page of all team issues
+func (m ListModel) AppendAllIssues(issues []linear.Issue, pageInfo linear.PageInfo) ListModel {
+	m.allIssues = appendNewIssues(m.allIssues, issues)
+	m.allPageInfo = pageInfo
+	m.loadingMore = false
+	if m.showAllIssues {
+		m.issues = m.allIssues
+		m.groupIssuesByState()
+		m.applyFilter()
+	}
+	m.findCurrentIssue()
+	return m
+}
+
+// MergeUpdatedIssues applies issues changed since the last sync: open issues
+// are updated or added, completed, archived and deleted ones dropped, and my
+// issues follow the assignee
+func (m ListModel) MergeUpdatedIssues(updated []linear.Issue, viewerID string) ListModel {
+	now := time.Now()
+	highlighted := make(map[string]time.Time, len(m.highlighted))
+	for id, at := range m.highlighted {
+		highlighted[id] = at
+	}
+	for _, issue := range updated {
+		open := issue.State.Type != "completed" && issue.ArchivedAt == ""
+		if prev, ok := m.Issue(issue.ID); open && (!ok || rowChanged(prev, issue)) {
+			highlighted[issue.ID] = now
+		}
+		mine := open && issue.Assignee != nil && issue.Assignee.ID == viewerID
+		m.allIssues = upsertIssue(m.allIssues, issue, open)
+		m.myIssues = upsertIssue(m.myIssues, issue, mine)
+	}
+	m.highlighted = highlighted
+	if m.showAllIssues {
+		m.issues = m.allIssues
+	} else {
+		m.issues = m.myIssues
+	}
+	m.groupIssuesByState()
+	m.applyFilter()
+	m.findCurrentIssue()
+	return m
+}
+
+// rowChanged reports whether an update changes what the issue's row shows
+func rowChanged(prev, next linear.Issue) bool {
+	id := func(u *linear.User) string {
+		if u == nil {
+			return ""
+		}
+		return u.ID
+	}
+	return prev.Title != next.Title ||
+		prev.Priority != next.Priority ||
+		prev.State.ID != next.State.ID ||
+		id(prev.Assignee) != id(next.Assignee) ||
+		!reflect.DeepEqual(prev.Estimate, next.Estimate) ||
+		!reflect.DeepEqual(prev.Cycle, next.Cycle)
+}
+
+// RemoveIssue drops an issue that was deleted or archived on Linear
+func (m ListModel) RemoveIssue(id string) ListModel {
+	m.allIssues = upsertIssue(m.allIssues, linear.Issue{ID: id}, false)
+	m.myIssues = upsertIssue(m.myIssues, linear.Issue{ID: id}, false)
+	if m.showAllIssues {
+		m.issues = m.allIssues
+	} else {
+		m.issues = m.myIssues
+	}
+	m.groupIssuesByState()
+	m.applyFilter()
+	m.findCurrentIssue()
+	return m
+}
+
+// ExpireHighlights removes highlights older than HighlightDuration
+func (m ListModel) ExpireHighlights(now time.Time) ListModel {
+	highlighted := make(map[string]time.Time, len(m.highlighted))
+	for id, at := range m.highlighted {
+		if now.Sub(at) < HighlightDuration {
+			highlighted[id] = at
+		}
+	}
+	m.highlighted = highlighted
+	return m
+}
+
+// HasHighlights reports whether any rows are highlighted
+func (m ListModel) HasHighlights() bool {
+	return len(m.highlighted) > 0
+}
+
+// upsertIssue replaces or adds the issue when keep is true, and removes it otherwise
+func upsertIssue(issues []linear.Issue, issue linear.Issue, keep bool) []linear.Issue {
+	for i := range issues {
+		if issues[i].ID == issue.ID {
+			if keep {
+				issues[i] = issue
+				return issues
+			}
+			return append(issues[:i:i], issues[i+1:]...)
+		}
+	}
+	if keep {
+		return append(issues, issue)
+	}
+	return issues
+}
+
+// appendNewIssues appends the issues not already present in existing
+func appendNewIssues(existing, issues []linear.Issue) []linear.Issue {
+	seen := make(map[string]bool, len(existing))
+	for _, issue := range existing {
+		seen[issue.ID] = true
+	}
+	for _, issue := range issues {
+		if !seen[issue.ID] {
+			existing = append(existing, issue)
+			seen[issue.ID] = true
+		}
+	}
+	return existing
+}
+
 func (m ListModel) ToggleShowAll() ListModel {
 	m.showAllIssues = !m.showAllIssues
 	if m.showAllIssues {
@@ -958,6 +1187,52 @@ func (m ListModel) ToggleShowAll() ListModel {
 	return m
 }
 
+func (m ListModel) MyIssues() []linear.Issue {
+	return m.myIssues
+}
+
+func (m ListModel) AllIssues() []linear.Issue {
+	return m.allIssues
+}
+
+func (m ListModel) MyPageInfo() linear.PageInfo {
+	return m.myPageInfo
+}
+
+func (m ListModel) AllPageInfo() linear.PageInfo {
+	return m.allPageInfo
+}
+
+// SetRefreshing toggles the indicator shown while cached issues are revalidated
+func (m ListModel) SetRefreshing(refreshing bool) ListModel {
+	m.refreshing = refreshing
+	return m
+}
+
+// SetOffline toggles the indicator shown while Linear is unreachable
+func (m ListModel) SetOffline(offline bool) ListModel {
+	m.offline = offline
+	return m
+}
+
+// SetPending sets the number of queued offline changes shown in the header
+func (m ListModel) SetPending(pending int) ListModel {
+	m.pending = pending
+	return m
+}
+
+// Issue returns the loaded issue with the given ID
+func (m ListModel) Issue(id string) (linear.Issue, bool) {
+	for _, issues := range [][]linear.Issue{m.myIssues, m.allIssues, m.issues} {
+		for _, issue := range issues {
+			if issue.ID == id {
+				return issue, true
+			}
+		}
+	}
+	return linear.Issue{}, false
+}
+
 func (m ListModel) ShowingAllIssues() bool {
 	return m.showAllIssues
 }
@@ -998,8 +1273,18 @@ func (m ListModel) MoveCursorPrev() ListModel {
 }
 
 func (m ListModel) SetStates(states []linear.State) ListModel {
+	// Keep the active tab when states are reloaded, e.g. after revalidation
+	activeStateID := ""
+	if m.activeState < len(m.states) {
+		activeStateID = m.states[m.activeState].ID
+	}
 	m.states = states
 	m.findDefaultState()
+	for i, state := range states {
+		if activeStateID != "" && state.ID == activeStateID {
+			m.activeState = i
+		}
+	}
 	m.applyFilter()
 	return m
 }
@@ -1010,6 +1295,13 @@ func (m ListModel) SetError(err error) ListModel {
 	return m
 }
 
+// SetStatus shows a transient status line, e.g. while requests are retried.
+// An empty status clears it.
+func (m ListModel) SetStatus(status string) ListModel {
+	m.status = status
+	return m
+}
+
 func (m ListModel) IsLoading() bool {
 	return m.loading
 }
@@ -1033,7 +1325,7 @@ func (m *ListModel) findCurrentIssue() {
 			m.currentIssue = issue
 			return
 		}
-		if issue.BranchName != "" && m.currentBranch == issue.BranchName {
+		if m.branches.Matches(m.currentBranch, *issue) {
 			m.currentIssue = issue
 			return
 		}
@@ -1041,6 +1333,13 @@ func (m *ListModel) findCurrentIssue() {
 	m.currentIssue = nil
 }
 
+// SetBranchNamer sets how issue branches are named, nil for Linear's names
+func (m ListModel) SetBranchNamer(namer *branch.Namer) ListModel {
+	m.branches = namer
+	m.findCurrentIssue()
+	return m
+}
+
 func (m ListModel) SetWorkingDir(dir string) ListModel {
 	m.workingDir = dir
 	return m
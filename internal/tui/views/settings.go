@@ -2,10 +2,10 @@ package views
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 
 	"linc/internal/config"
+	"linc/internal/provider"
 	"linc/internal/tui/messages"
 	"linc/internal/tui/styles"
 
@@ -13,26 +13,25 @@ import (
 )
 
 type SettingsModel struct {
-	cfg              *config.Config
-	workspace        *config.Workspace
-	providers        []string
-	currentProvider  string
-	providerCursor   int
-	editingProvider  bool
-	saved            bool
-	err              error
+	cfg             *config.Config
+	workspace       *config.Workspace
+	providers       []provider.Info
+	currentProvider string
+	providerCursor  int
+	editingProvider bool
+	saved           bool
+	err             error
 }
 
-func NewSettingsModel(cfg *config.Config, workspace *config.Workspace, providers []string) SettingsModel {
-	// Sort providers for consistent display
-	sort.Strings(providers)
-
+// NewSettingsModel creates the settings view; providers are sorted by ID, see
+// provider.Registry.Describe
+func NewSettingsModel(cfg *config.Config, workspace *config.Workspace, providers []provider.Info) SettingsModel {
 	currentProvider := cfg.GetProvider()
 
 	// Find cursor position for current provider
 	cursorIdx := 0
 	for i, p := range providers {
-		if p == currentProvider {
+		if p.ID == currentProvider {
 			cursorIdx = i
 			break
 		}
@@ -97,18 +96,22 @@ func (m SettingsModel) handleProviderInput(msg tea.KeyMsg) (SettingsModel, tea.C
 		}
 	case "enter":
 		if len(m.providers) > 0 {
-			selectedProvider := m.providers[m.providerCursor]
-			m.currentProvider = selectedProvider
+			selected := m.providers[m.providerCursor]
+			if selected.Err != nil {
+				// Unavailable providers can't be selected; the reason is shown
+				return m, nil
+			}
+			m.currentProvider = selected.ID
 			m.editingProvider = false
 			// Auto-save on selection
-			return m, m.saveSettings(selectedProvider)
+			return m, m.saveSettings(selected.ID)
 		}
 		m.editingProvider = false
 		return m, nil
 	case "esc":
 		// Cancel - restore cursor to current provider
 		for i, p := range m.providers {
-			if p == m.currentProvider {
+			if p.ID == m.currentProvider {
 				m.providerCursor = i
 				break
 			}
@@ -155,6 +158,9 @@ func (m SettingsModel) View() string {
 	// Provider section
 	s.WriteString(styles.DetailLabelStyle.Render("AI Provider") + "\n")
 	s.WriteString(fmt.Sprintf("  %s\n", styles.DetailValueStyle.Render(m.currentProvider)))
+	if info, ok := m.providerInfo(m.currentProvider); ok && info.Err != nil {
+		s.WriteString("  " + styles.ErrorStyle.Render("Unavailable: "+info.Err.Error()) + "\n")
+	}
 	s.WriteString("\n")
 
 	// Config file location
@@ -180,25 +186,68 @@ func (m SettingsModel) renderProviderSelectView() string {
 
 	s.WriteString(styles.TitleStyle.Render("Select AI Provider") + "\n\n")
 
-	for i, provider := range m.providers {
+	for i, info := range m.providers {
 		cursor := "  "
 		if m.providerCursor == i {
 			cursor = styles.CursorStyle.Render("> ")
 		}
 
-		label := provider
-		if provider == m.currentProvider {
+		label := info.ID
+		if _, ok := m.cfg.Providers[info.ID]; ok {
+			label += " (custom)"
+		}
+		if info.ID == m.currentProvider {
 			label += " (current)"
 		}
 
-		if m.providerCursor == i {
+		switch {
+		case info.Err != nil:
+			// Greyed out with the reason, and not selectable
+			s.WriteString(cursor + styles.DisabledItemStyle.Render(label+" - "+info.Err.Error()) + "\n")
+		case m.providerCursor == i:
 			s.WriteString(cursor + styles.SelectedItemStyle.Render(label) + "\n")
-		} else {
+		default:
 			s.WriteString(cursor + label + "\n")
 		}
 	}
 
+	if m.providerCursor < len(m.providers) {
+		s.WriteString("\n" + styles.SubtitleStyle.Render(describeCapabilities(m.providers[m.providerCursor])))
+	}
+
 	s.WriteString(styles.HelpStyle.Render("\nj/k: navigate • enter: select • esc: cancel"))
 
 	return s.String()
 }
+
+func (m SettingsModel) providerInfo(id string) (provider.Info, bool) {
+	for _, info := range m.providers {
+		if info.ID == id {
+			return info, true
+		}
+	}
+	return provider.Info{}, false
+}
+
+// describeCapabilities summarises what a provider supports, e.g.
+// "Claude Code: plan mode • resume • prompts up to 128 KiB"
+func describeCapabilities(info provider.Info) string {
+	caps := info.Capabilities
+	var features []string
+	if caps.PlanMode {
+		features = append(features, "plan mode")
+	}
+	if caps.Resume {
+		features = append(features, "resume")
+	}
+	if caps.MCPConfig {
+		features = append(features, "Linear MCP")
+	}
+	if caps.MaxPromptSize > 0 {
+		features = append(features, fmt.Sprintf("prompts up to %d KiB", (caps.MaxPromptSize+1)/1024))
+	}
+	if len(features) == 0 {
+		features = append(features, "no plan mode")
+	}
+	return info.Name + ": " + strings.Join(features, " • ")
+}
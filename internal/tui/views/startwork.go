@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"linc/internal/git"
 	"linc/internal/linear"
 	"linc/internal/tui/messages"
 	"linc/internal/tui/styles"
@@ -14,13 +15,46 @@ import (
 
 type StartWorkModel struct {
 	issue         linear.Issue
+	linearBranch  string // Linear's suggested branch, when the issue's is templated
 	commentInput  textinput.Model
 	useBranchName bool
+	worktree      bool
+	fromHead      bool
 	planMode      bool
-	focusIndex    int // 0 = useBranch, 1 = planMode, 2 = comment, 3 = start button, 4 = checkout only
+	resume        bool
+	resumable     string      // describes the agent session that can be resumed, empty if none
+	branch        string      // branch checked out in the current working tree
+	changes       *git.Status // uncommitted state of the working tree, nil until loaded
+	onChanges     int         // what to do with local changes when switching branches
+	base          string      // where a new branch for the issue starts, empty if it exists
+	focusIndex    int
 	err           error
 }
 
+// Focusable fields, in tab order
+const (
+	focusUseBranch = iota
+	focusWorktree  // only when the issue has a branch name
+	focusFromHead  // only when the issue's branch would be created
+	focusChanges   // only when switching branches would meet local changes
+	focusPlanMode
+	focusResume // only when a session can be resumed
+	focusComment
+	focusStart
+	focusCheckoutOnly
+	focusCount
+)
+
+// What to do with local changes when switching to the issue's branch
+const (
+	changesStash = iota
+	changesCarry
+	changesAbort
+	changesCount
+)
+
+var changesLabels = [changesCount]string{"Stash and switch", "Carry over", "Abort"}
+
 func NewStartWorkModel(issue linear.Issue) StartWorkModel {
 	ti := textinput.New()
 	ti.Placeholder = "Add a comment (optional, syncs to Linear)"
@@ -32,7 +66,138 @@ func NewStartWorkModel(issue linear.Issue) StartWorkModel {
 		commentInput:  ti,
 		useBranchName: true,
 		planMode:      true,
-		focusIndex:    0,
+		focusIndex:    focusUseBranch,
+	}
+}
+
+// SetResumable offers to resume a previous agent session on the issue,
+// described by label, e.g. "claude, Jan 2 15:04". An empty label offers none.
+func (m StartWorkModel) SetResumable(label string) StartWorkModel {
+	m.resumable = label
+	if label == "" {
+		m.resume = false
+		if m.focusIndex == focusResume {
+			m.focusIndex = focusPlanMode
+		}
+	}
+	return m
+}
+
+// SetLinearBranch records Linear's suggested branch, which is worked on
+// instead of the templated one if only it exists
+func (m StartWorkModel) SetLinearBranch(branch string) StartWorkModel {
+	m.linearBranch = branch
+	return m
+}
+
+// SetIssueBranch sets the branch the issue is worked on in, once it's known
+// which of the templated and Linear's branches to use
+func (m StartWorkModel) SetIssueBranch(branch string) StartWorkModel {
+	if branch != "" {
+		m.issue.BranchName = branch
+	}
+	return m
+}
+
+// SetError shows why work can't be started
+func (m StartWorkModel) SetError(err error) StartWorkModel {
+	m.err = err
+	return m
+}
+
+// SetWorkingTree tells the model which branch is checked out, what
+// uncommitted state the working tree is in and, if the issue's branch
+// doesn't exist yet, the ref it would start from
+func (m StartWorkModel) SetWorkingTree(branch string, changes *git.Status, base string) StartWorkModel {
+	m.branch = branch
+	m.changes = changes
+	m.base = base
+	return m
+}
+
+// offersFromHead reports whether the issue's branch would be created from a
+// base branch that could be swapped for the current HEAD
+func (m StartWorkModel) offersFromHead() bool {
+	return m.base != ""
+}
+
+// meetsChanges reports whether checking out the issue's branch here would
+// have to deal with local changes
+func (m StartWorkModel) meetsChanges() bool {
+	return m.changes != nil && m.changes.Dirty() &&
+		m.issue.BranchName != "" && m.issue.BranchName != m.branch && !m.worktree
+}
+
+// move shifts the focus by delta fields, skipping toggles that don't apply
+func (m StartWorkModel) move(delta int) (StartWorkModel, tea.Cmd) {
+	m.focusIndex = (m.focusIndex + delta + focusCount) % focusCount
+	for !m.focusable(m.focusIndex) {
+		m.focusIndex = (m.focusIndex + delta + focusCount) % focusCount
+	}
+	if m.focusIndex == focusComment {
+		m.commentInput.Focus()
+		return m, textinput.Blink
+	}
+	m.commentInput.Blur()
+	return m, nil
+}
+
+func (m StartWorkModel) focusable(index int) bool {
+	switch index {
+	case focusWorktree:
+		return m.issue.BranchName != ""
+	case focusResume:
+		return m.resumable != ""
+	case focusFromHead:
+		return m.offersFromHead()
+	case focusChanges:
+		return m.meetsChanges()
+	}
+	return true
+}
+
+// start starts work on the issue, or only checks out its branch, handling
+// local changes in the way chosen
+func (m StartWorkModel) start(checkoutOnly bool) (StartWorkModel, tea.Cmd) {
+	msg := messages.StartClaudeMsg{
+		Issue:        m.issue,
+		LinearBranch: m.linearBranch,
+		Comment:      m.commentInput.Value(),
+		UseBranch:    m.useBranchName,
+		Worktree:     m.worktree,
+		FromHead:     m.fromHead && m.offersFromHead(),
+		PlanMode:     m.planMode,
+		Resume:       m.resume,
+	}
+	if checkoutOnly {
+		msg = messages.StartClaudeMsg{
+			Issue:        m.issue,
+			LinearBranch: m.linearBranch,
+			Comment:      m.commentInput.Value(),
+			UseBranch:    true,
+			Worktree:     m.worktree,
+			FromHead:     m.fromHead && m.offersFromHead(),
+			CheckoutOnly: true,
+		}
+	}
+
+	if msg.UseBranch && m.meetsChanges() {
+		if op := m.changes.Operation; op != "" {
+			m.err = fmt.Errorf("a %s is in progress on %s, finish it first or work in a worktree", op, m.branch)
+			return m, nil
+		}
+		switch m.onChanges {
+		case changesStash:
+			msg.Stash = true
+		case changesAbort:
+			return m, func() tea.Msg {
+				return messages.SwitchToDetailMsg{Issue: m.issue}
+			}
+		}
+	}
+
+	return m, func() tea.Msg {
+		return msg
 	}
 }
 
@@ -45,27 +210,16 @@ func (m StartWorkModel) Update(msg tea.Msg) (StartWorkModel, tea.Cmd) {
 	case tea.KeyMsg:
 		// Shift+enter starts Claude from any field
 		if msg.String() == "shift+enter" {
-			return m, func() tea.Msg {
-				return messages.StartClaudeMsg{
-					Issue:     m.issue,
-					Comment:   m.commentInput.Value(),
-					UseBranch: m.useBranchName,
-					PlanMode:  m.planMode,
-				}
-			}
+			return m.start(false)
 		}
 
 		// Handle text input first when focused on comment field
-		if m.focusIndex == 2 {
+		if m.focusIndex == focusComment {
 			switch msg.String() {
 			case "tab", "down":
-				m.focusIndex = 3
-				m.commentInput.Blur()
-				return m, nil
+				return m.move(1)
 			case "shift+tab", "up":
-				m.focusIndex = 1
-				m.commentInput.Blur()
-				return m, nil
+				return m.move(-1)
 			case "esc":
 				return m, func() tea.Msg {
 					return messages.SwitchToDetailMsg{Issue: m.issue}
@@ -79,46 +233,36 @@ func (m StartWorkModel) Update(msg tea.Msg) (StartWorkModel, tea.Cmd) {
 
 		switch msg.String() {
 		case "tab", "down":
-			m.focusIndex = (m.focusIndex + 1) % 5
-			if m.focusIndex == 2 {
-				m.commentInput.Focus()
-				return m, textinput.Blink
-			}
-			return m, nil
+			return m.move(1)
 		case "shift+tab", "up":
-			m.focusIndex--
-			if m.focusIndex < 0 {
-				m.focusIndex = 4
-			}
-			if m.focusIndex == 2 {
-				m.commentInput.Focus()
-				return m, textinput.Blink
+			return m.move(-1)
+		case "left", "right":
+			if m.focusIndex == focusChanges {
+				delta := 1
+				if msg.String() == "left" {
+					delta = changesCount - 1
+				}
+				m.onChanges = (m.onChanges + delta) % changesCount
 			}
 			return m, nil
 		case "enter", " ":
 			switch m.focusIndex {
-			case 0:
+			case focusUseBranch:
 				m.useBranchName = !m.useBranchName
-			case 1:
+			case focusWorktree:
+				m.worktree = !m.worktree
+			case focusFromHead:
+				m.fromHead = !m.fromHead
+			case focusChanges:
+				m.onChanges = (m.onChanges + 1) % changesCount
+			case focusPlanMode:
 				m.planMode = !m.planMode
-			case 3:
-				return m, func() tea.Msg {
-					return messages.StartClaudeMsg{
-						Issue:     m.issue,
-						Comment:   m.commentInput.Value(),
-						UseBranch: m.useBranchName,
-						PlanMode:  m.planMode,
-					}
-				}
-			case 4:
-				return m, func() tea.Msg {
-					return messages.StartClaudeMsg{
-						Issue:        m.issue,
-						Comment:      m.commentInput.Value(),
-						UseBranch:    true,
-						CheckoutOnly: true,
-					}
-				}
+			case focusResume:
+				m.resume = !m.resume
+			case focusStart:
+				return m.start(false)
+			case focusCheckoutOnly:
+				return m.start(true)
 			}
 			return m, nil
 		case "esc":
@@ -139,16 +283,31 @@ func (m StartWorkModel) View() string {
 	s.WriteString(styles.SubtitleStyle.Render(m.issue.Title) + "\n\n")
 
 	// Checkboxes
-	s.WriteString(m.renderCheckbox("Use Linear branch name", m.useBranchName, m.focusIndex == 0))
+	s.WriteString(renderCheckbox("Use issue branch", m.useBranchName, m.focusIndex == focusUseBranch))
 	if m.issue.BranchName != "" {
 		s.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("  (%s)", m.issue.BranchName)))
 	}
 	s.WriteString("\n")
-	s.WriteString(m.renderCheckbox("Start in plan mode", m.planMode, m.focusIndex == 1) + "\n\n")
+	if m.issue.BranchName != "" {
+		s.WriteString(renderCheckbox("Work in a separate git worktree", m.worktree, m.focusIndex == focusWorktree) + "\n")
+	}
+	if m.offersFromHead() {
+		s.WriteString(renderCheckbox("Branch from current HEAD", m.fromHead, m.focusIndex == focusFromHead))
+		s.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("  (new branch, instead of %s)", m.base)) + "\n")
+	}
+	if m.meetsChanges() {
+		s.WriteString(m.renderChanges())
+	}
+	s.WriteString(renderCheckbox("Start in plan mode", m.planMode, m.focusIndex == focusPlanMode) + "\n")
+	if m.resumable != "" {
+		s.WriteString(renderCheckbox("Resume previous session", m.resume, m.focusIndex == focusResume))
+		s.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("  (%s)", m.resumable)) + "\n")
+	}
+	s.WriteString("\n")
 
 	// Comment input
 	commentStyle := styles.InputStyle
-	if m.focusIndex == 2 {
+	if m.focusIndex == focusComment {
 		commentStyle = styles.FocusedInputStyle
 	}
 	s.WriteString(styles.DetailLabelStyle.Render("Comment:") + "\n")
@@ -156,11 +315,11 @@ func (m StartWorkModel) View() string {
 
 	// Buttons
 	startBtnStyle := styles.ButtonStyle
-	if m.focusIndex == 3 {
+	if m.focusIndex == focusStart {
 		startBtnStyle = styles.ActiveButtonStyle
 	}
 	checkoutBtnStyle := styles.ButtonStyle
-	if m.focusIndex == 4 {
+	if m.focusIndex == focusCheckoutOnly {
 		checkoutBtnStyle = styles.ActiveButtonStyle
 	}
 	s.WriteString(startBtnStyle.Render("Start Claude") + "  " + checkoutBtnStyle.Render("Checkout Only") + "\n")
@@ -176,7 +335,30 @@ func (m StartWorkModel) View() string {
 	return s.String()
 }
 
-func (m StartWorkModel) renderCheckbox(label string, checked bool, focused bool) string {
+// renderChanges warns about the working tree's local changes and offers
+// what to do with them
+func (m StartWorkModel) renderChanges() string {
+	var s strings.Builder
+	s.WriteString("\n" + styles.WarningStyle.Render(fmt.Sprintf("  Uncommitted changes on %s: %s", m.branch, m.changes)) + "\n")
+
+	cursor := "  "
+	labelStyle := styles.CheckboxStyle
+	if m.focusIndex == focusChanges {
+		cursor = styles.CursorStyle.Render("> ")
+		labelStyle = styles.SelectedItemStyle
+	}
+	s.WriteString(cursor + labelStyle.Render("Local changes:"))
+	for i, label := range changesLabels {
+		option := "( ) " + label
+		if i == m.onChanges {
+			option = styles.CheckboxCheckedStyle.Render("(•)") + " " + label
+		}
+		s.WriteString("  " + option)
+	}
+	return s.String() + "\n\n"
+}
+
+func renderCheckbox(label string, checked bool, focused bool) string {
 	checkbox := "[ ]"
 	if checked {
 		checkbox = styles.CheckboxCheckedStyle.Render("[x]")
@@ -207,6 +389,14 @@ func (m StartWorkModel) UseBranchName() bool {
 	return m.useBranchName
 }
 
+func (m StartWorkModel) Worktree() bool {
+	return m.worktree
+}
+
 func (m StartWorkModel) PlanMode() bool {
 	return m.planMode
 }
+
+func (m StartWorkModel) Resume() bool {
+	return m.resume
+}
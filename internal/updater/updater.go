@@ -163,7 +163,7 @@ func promptForUpdate(current, latest, releaseURL string, state updateState) bool
 		return false
 
 	default: // "l", "later", or anything else
-		fmt.Println("\n  OK, I'll remind you next time.\n")
+		fmt.Print("\n  OK, I'll remind you next time.\n\n")
 		return false
 	}
 }
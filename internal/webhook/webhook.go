@@ -0,0 +1,127 @@
+// Package webhook accepts Linear webhook payloads on a local HTTP listener,
+// e.g. forwarded by a relay, so linc can pick up changes as they happen.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// maxPayloadSize bounds the webhook bodies read
+const maxPayloadSize = 1 << 20
+
+// Event is the part of a Linear webhook payload linc acts on
+type Event struct {
+	Action string // create, update or remove
+	Type   string // Issue, Comment, ...
+	ID     string // ID of the changed entity
+	TeamID string
+}
+
+// Listener serves the webhook endpoint and hands events to its handler
+type Listener struct {
+	mu       sync.Mutex
+	handler  func(Event)
+	secret   string
+	listener net.Listener
+	server   *http.Server
+}
+
+// Listen starts accepting webhooks on addr. When secret is set, payloads
+// must carry a valid Linear-Signature header.
+func Listen(addr, secret string) (*Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Listener{secret: secret, listener: ln}
+	l.server = &http.Server{Handler: l}
+	go func() { _ = l.server.Serve(ln) }()
+	return l, nil
+}
+
+// Addr returns the address the listener accepts connections on
+func (l *Listener) Addr() string {
+	return l.listener.Addr().String()
+}
+
+// SetHandler sets the function called for each event; nil drops events
+func (l *Listener) SetHandler(fn func(Event)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handler = fn
+}
+
+func (l *Listener) Close() error {
+	return l.server.Close()
+}
+
+func (l *Listener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if l.secret != "" && !validSignature(body, r.Header.Get("Linear-Signature"), l.secret) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var payload struct {
+		Action string `json:"action"`
+		Type   string `json:"type"`
+		Data   struct {
+			ID     string `json:"id"`
+			TeamID string `json:"teamId"`
+			Team   *struct {
+				ID string `json:"id"`
+			} `json:"team"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	event := Event{
+		Action: payload.Action,
+		Type:   payload.Type,
+		ID:     payload.Data.ID,
+		TeamID: payload.Data.TeamID,
+	}
+	if event.TeamID == "" && payload.Data.Team != nil {
+		event.TeamID = payload.Data.Team.ID
+	}
+
+	l.mu.Lock()
+	handler := l.handler
+	l.mu.Unlock()
+	if handler != nil {
+		handler(event)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// validSignature checks the hex HMAC-SHA256 of the body Linear signs
+// webhooks with
+func validSignature(body []byte, signature, secret string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -1,21 +1,31 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	"linc/internal/auth"
+	"linc/internal/branch"
 	"linc/internal/config"
+	"linc/internal/git"
+	"linc/internal/journal"
 	"linc/internal/linear"
+	"linc/internal/mcp"
 	"linc/internal/provider"
+	"linc/internal/provider/aider"
 	"linc/internal/provider/claude"
+	"linc/internal/provider/codex"
 	"linc/internal/provider/echo"
+	"linc/internal/provider/gemini"
+	"linc/internal/provider/opencode"
+	"linc/internal/session"
 	"linc/internal/tui"
 	"linc/internal/tui/messages"
 	"linc/internal/tui/views"
 	"linc/internal/updater"
+	"linc/internal/webhook"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -30,16 +40,20 @@ func main() {
 		return
 	}
 
+	// Subcommands run without the update check and TUI
+	if len(os.Args) > 1 && (os.Args[1] == "prompt" || os.Args[1] == "sessions" || os.Args[1] == "worktrees") {
+		if err := runSubcommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Check for updates (non-blocking, only prompts if update available)
 	if updater.CheckForUpdate(version) {
 		// User chose to update, exit so they can restart
 		return
 	}
-	// Initialize provider registry
-	registry := provider.NewRegistry()
-	registry.Register("claude", claude.New())
-	registry.Register("echo", echo.New())
-
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -47,6 +61,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Initialize provider registry
+	registry := provider.NewRegistry()
+	registry.Register("claude", claude.New())
+	registry.Register("opencode", opencode.New())
+	if p, err := aider.New(cfg.Aider); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, aider is unavailable\n", err)
+	} else {
+		registry.Register("aider", p)
+	}
+	registry.Register("echo", echo.New())
+	registry.RegisterIfAvailable("codex", codex.New())
+	registry.RegisterIfAvailable("gemini", gemini.New())
+
+	// Custom providers declared in config
+	if err := registry.RegisterCommands(cfg.Providers); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	// Get current directory
 	currentDir, err := os.Getwd()
 	if err != nil {
@@ -54,6 +86,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Bring back local changes stashed when starting work switched away
+	// from this branch
+	restoreStash()
+
 	// Check if current directory (or parent) has a workspace mapped
 	ws := cfg.GetWorkspaceForDirectory(currentDir)
 
@@ -72,16 +108,49 @@ func main() {
 		}
 	}
 
+	// An invalid branch name template falls back to Linear's branch names
+	if _, err := branch.New(cfg, ws.ID, git.GetMainRepoRoot()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: branch name template: %v, using Linear's branch names\n", err)
+	}
+
 	// Create Linear client
-	client := linear.NewClient(ws.APIKey)
+	client := newLinearClient(cfg, ws.APIKey)
 
 	// Pass version to TUI
 	tui.Version = version
 
-	// Create and run TUI (loop to handle add-workspace flow)
+	// Accept Linear webhooks locally if configured; the list polls otherwise
+	var listener *webhook.Listener
+	if addr, secret := cfg.GetWebhookListen(); addr != "" {
+		listener, err = webhook.Listen(addr, secret)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: not accepting webhooks: %v\n", err)
+		} else {
+			defer listener.Close()
+		}
+	}
+
+	// Sessions handed over to agents that have exited since get their end
+	closeUnseenSessions()
+
+	// Create and run TUI (loop to handle add-workspace flow and follow-ups
+	// after a supervised agent session)
+	var finished *finishedWork
 	for {
-		model := tui.NewRootModel(client, cfg, ws, cfg.Workspaces, currentDir, registry.List())
+		model := tui.NewRootModel(client, cfg, ws, cfg.Workspaces, currentDir, registry.Describe())
+		if finished != nil {
+			model = model.WithFollowUp(finished.issue, finished.branch, finished.summary, finished.err)
+			finished = nil
+		}
 		p := tea.NewProgram(model)
+		client.SetRetryNotifier(func(event linear.RetryEvent) {
+			p.Send(messages.RetryingMsg{Event: event})
+		})
+		if listener != nil {
+			listener.SetHandler(func(event webhook.Event) {
+				p.Send(messages.WebhookEventMsg{Event: event})
+			})
+		}
 
 		finalModel, err := p.Run()
 		if err != nil {
@@ -94,6 +163,21 @@ func main() {
 			return
 		}
 
+		// Work continues in the workspace last switched to in the TUI, with
+		// its API key
+		if current := rootModel.Workspace(); current != nil && (current.ID != ws.ID || current.APIKey != ws.APIKey) {
+			ws = current
+			client = newLinearClient(cfg, ws.APIKey)
+		}
+
+		// Outside the TUI, report retries inline with the progress output
+		client.SetRetryNotifier(func(event linear.RetryEvent) {
+			fmt.Printf(" (%s)", event)
+		})
+		if listener != nil {
+			listener.SetHandler(nil)
+		}
+
 		// Handle add new workspace: run auth flow, then restart TUI
 		if rootModel.ShouldAddNewWorkspace() {
 			newWs, err := addNewWorkspace(cfg, currentDir)
@@ -102,86 +186,303 @@ func main() {
 				os.Exit(1)
 			}
 			ws = newWs
-			client = linear.NewClient(ws.APIKey)
+			client = newLinearClient(cfg, ws.APIKey)
+			continue
+		}
+
+		// Handle rejected API key: prompt for a new one, then restart TUI
+		if rootModel.ShouldReauthenticate() {
+			renewedWs, err := auth.PromptForRenewedAPIKey(cfg, ws, workspaceInfoFetcher(cfg))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			ws = renewedWs
+			client = newLinearClient(cfg, ws.APIKey)
 			continue
 		}
 
 		// Check if we need to start an agent
 		if startMsg := rootModel.ShouldStartClaude(); startMsg != nil {
-			// Checkout only mode - just checkout branch and exit
-			if startMsg.CheckoutOnly {
-				if startMsg.Issue.BranchName != "" {
-					checkoutBranch(startMsg.Issue.BranchName)
-				} else {
-					fmt.Println("No branch name available for this issue")
-				}
-				return
+			finished, err = runStartWork(client, cfg, ws, registry, startMsg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if finished != nil {
+				continue
 			}
+		}
+
+		// Normal exit
+		return
+	}
+}
 
-			// Fetch full issue context (comments, attachments)
-			fmt.Print("Fetching issue context...")
-			issueWithContext, err := client.GetIssueWithContext(startMsg.Issue.ID)
+func runSubcommand(name string, args []string) error {
+	switch name {
+	case "sessions":
+		return runSessionsCommand(args)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if name == "worktrees" {
+		return runWorktreesCommand(cfg, args)
+	}
+	return runPromptCommand(cfg, args)
+}
+
+// linearOptions returns the Linear client options derived from config and environment
+func linearOptions(cfg *config.Config) []linear.Option {
+	var opts []linear.Option
+	if url := cfg.GetLinearAPIURL(); url != "" {
+		opts = append(opts, linear.WithEndpoint(url))
+	}
+	return opts
+}
+
+func newLinearClient(cfg *config.Config, apiKey string) *linear.Client {
+	client := linear.NewClient(apiKey, linearOptions(cfg)...)
+	client.SetMaxIssues(cfg.MaxIssues)
+	return client
+}
+
+// finishedWork describes a supervised agent session that has ended
+type finishedWork struct {
+	issue   linear.Issue
+	branch  string
+	summary string // draft summary comment
+	err     error  // how the session ended
+}
+
+// runStartWork prepares the Linear issue and launches the configured provider.
+// With a provider that replaces the process, this does not return on success.
+// When supervising, the agent runs as a child process and the finished
+// session is returned for follow-ups.
+func runStartWork(client *linear.Client, cfg *config.Config, ws *config.Workspace, registry *provider.Registry, startMsg *messages.StartClaudeMsg) (*finishedWork, error) {
+	// Checkout only mode - just checkout branch and exit
+	if startMsg.CheckoutOnly {
+		resolveIssueBranch(startMsg)
+		switch {
+		case startMsg.Issue.BranchName == "":
+			fmt.Println("No branch name available for this issue")
+		case startMsg.Worktree:
+			leave, err := enterWorktree(cfg, startMsg.Issue.BranchName, startMsg.FromHead)
 			if err != nil {
-				fmt.Printf(" failed: %v\n", err)
-				// Fall back to the original issue without context
-				issueWithContext = &startMsg.Issue
-			} else {
-				fmt.Println(" done")
+				return nil, err
 			}
-
-			// Get organization info
-			var issueCtx *linear.IssueContext
-			orgID, orgName, err := client.GetWorkspaceInfo()
-			if err == nil {
-				issueCtx = &linear.IssueContext{
-					OrganizationID:   orgID,
-					OrganizationName: orgName,
-				}
+			leave()
+		default:
+			if err := switchBranch(cfg, startMsg); err != nil {
+				return nil, err
 			}
+		}
+		return nil, nil
+	}
+
+	// Get provider from config, failing before Linear is touched if it can't run
+	providerID := cfg.GetProvider()
+	prov, err := registry.Get(providerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := prov.Available(); err != nil {
+		return nil, fmt.Errorf("%s is not available: %w", prov.Name(), err)
+	}
+
+	if startMsg.UseBranch || startMsg.Worktree {
+		resolveIssueBranch(startMsg)
+	}
+
+	ctx := context.Background()
+
+	// Fetch full issue context (comments, attachments)
+	fmt.Print("Fetching issue context...")
+	issueWithContext, err := client.GetIssueWithContext(ctx, startMsg.Issue.ID)
+	if err != nil {
+		fmt.Printf(" failed: %v\n", err)
+		// Fall back to the original issue without context
+		issueWithContext = &startMsg.Issue
+	} else {
+		fmt.Println(" done")
+		// Keep the branch named by the configured scheme
+		issueWithContext.BranchName = startMsg.Issue.BranchName
+	}
+
+	// Get organization info
+	var issueCtx *linear.IssueContext
+	orgID, orgName, err := client.GetWorkspaceInfo(ctx)
+	if err == nil {
+		issueCtx = &linear.IssueContext{
+			OrganizationID:   orgID,
+			OrganizationName: orgName,
+		}
+	}
+
+	// Render the prompt for the branch and directory the agent will work in
+	var gitInfo provider.GitInfo
+	if startMsg.UseBranch || startMsg.Worktree {
+		gitInfo.Branch = startMsg.Issue.BranchName
+	}
+	if startMsg.Worktree && startMsg.Issue.BranchName != "" {
+		if gitInfo.Root, err = worktreePath(cfg, startMsg.Issue.BranchName); err != nil {
+			return nil, err
+		}
+	}
+	prompt, err := renderPrompt(cfg, ws.ID, "", gitInfo, *issueWithContext, startMsg.Comment, issueCtx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using the default prompt\n", err)
+		prompt = provider.BuildPrompt(*issueWithContext, startMsg.Comment, issueCtx)
+	}
+
+	req := provider.Request{
+		Issue:    *issueWithContext,
+		Comment:  startMsg.Comment,
+		Context:  issueCtx,
+		PlanMode: startMsg.PlanMode,
+		Prompt:   prompt,
+		Resume:   startMsg.Resume,
+	}
 
-			// Update Linear before starting agent
-			prepareLinearIssue(client, startMsg)
+	// Continue the issue's previous agent session, or start one that can
+	// be resumed later
+	dir, _ := os.Getwd()
+	if gitInfo.Root != "" {
+		dir = gitInfo.Root
+	}
+	if prov.Capabilities().Resume {
+		req.SessionID, req.Resume = agentSession(startMsg.Issue.ID, providerID, dir, startMsg.Resume)
+	}
+
+	if err := provider.Check(prov, req); err != nil {
+		return nil, err
+	}
 
-			// Get provider from config
-			providerID := cfg.GetProvider()
-			prov, err := registry.Get(providerID)
+	// Move into the issue's worktree or switch to its branch before touching
+	// Linear, so a failure leaves the issue as it was
+	if gitInfo.Root != "" {
+		leave, err := enterWorktree(cfg, startMsg.Issue.BranchName, startMsg.FromHead)
+		if err != nil {
+			return nil, err
+		}
+		// Once a supervised session ends, linc carries on where it started
+		defer leave()
+	} else if startMsg.UseBranch && startMsg.Issue.BranchName != "" {
+		if err := switchBranch(cfg, startMsg); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update Linear before starting agent, now that nothing is left to fail
+	prepareLinearIssue(ctx, client, ws.ID, startMsg)
+
+	closeUnseenSessions(startMsg.Issue.ID)
+	sess := &session.Session{
+		IssueID:    startMsg.Issue.ID,
+		Identifier: startMsg.Issue.Identifier,
+		Provider:   providerID,
+		PlanMode:   startMsg.PlanMode,
+		Branch:     git.GetCurrentBranch(),
+		Dir:        dir,
+		PID:        os.Getpid(),
+		HeadBefore: git.GetHead(),
+
+		AgentSessionID: req.SessionID,
+	}
+	recordSession(sess)
+
+	// Give the agent the Linear MCP server; its config holds the API key, so
+	// it is removed once the session ends
+	if enabled, url := cfg.LinearMCPEnabled(); enabled {
+		if prov.Capabilities().MCPConfig {
+			path, err := mcp.WriteLinearConfig(startMsg.Issue.Identifier, ws.APIKey, url)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			} else {
+				req.MCPConfig = path
+				defer os.Remove(path)
 			}
+		} else {
+			fmt.Printf("%s doesn't accept an MCP config, starting without the Linear MCP server\n", prov.Name())
+		}
+	}
 
-			// Execute provider (this may replace the process)
-			if err := prov.Exec(*issueWithContext, startMsg.Comment, issueCtx, startMsg.PlanMode); err != nil {
-				fmt.Fprintf(os.Stderr, "Error starting %s: %v\n", prov.Name(), err)
-				os.Exit(1)
-			}
+	if !cfg.Supervise {
+		// Execute provider (this may replace the process, leaving the
+		// session's end to be filled in once linc finds the agent gone).
+		// With an MCP config to remove afterwards, the agent runs as a child
+		// process instead.
+		var err error
+		if req.MCPConfig != "" {
+			err = provider.Run(prov, req)
+		} else {
+			err = prov.Exec(req)
 		}
+		sess.Ended(err, git.GetHead())
+		recordSession(sess)
+		if err != nil {
+			return nil, fmt.Errorf("starting %s: %w", prov.Name(), err)
+		}
+		return nil, nil
+	}
 
-		// Normal exit
-		return
+	err = provider.Run(prov, req)
+	sess.Ended(err, git.GetHead())
+	recordSession(sess)
+	return &finishedWork{
+		issue:   *issueWithContext,
+		branch:  git.GetCurrentBranch(),
+		summary: sessionSummary(prov.Name(), sess.HeadBefore),
+		err:     err,
+	}, nil
+}
+
+// sessionSummary drafts a comment describing the commits made since head
+func sessionSummary(providerName, head string) string {
+	var commits []string
+	if repo, err := git.Current(); err == nil && head != "" {
+		commits = repo.CommitsSince(head)
+	}
+	if len(commits) == 0 {
+		return fmt.Sprintf("Worked on this with %s, nothing committed yet.", providerName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Worked on this with %s:\n\n", providerName))
+	for _, commit := range commits {
+		sb.WriteString("- " + commit + "\n")
 	}
+	return sb.String()
 }
 
-func addNewWorkspace(cfg *config.Config, currentDir string) (*config.Workspace, error) {
-	fetchInfo := func(apiKey string) (*auth.WorkspaceInfo, error) {
-		id, name, err := linear.FetchWorkspaceInfo(apiKey)
+// workspaceInfoFetcher validates API keys against the configured Linear endpoint
+func workspaceInfoFetcher(cfg *config.Config) auth.WorkspaceInfoFetcher {
+	return func(apiKey string) (*auth.WorkspaceInfo, error) {
+		id, name, err := linear.FetchWorkspaceInfo(context.Background(), apiKey, linearOptions(cfg)...)
 		if err != nil {
 			return nil, err
 		}
 		return &auth.WorkspaceInfo{ID: id, Name: name}, nil
 	}
+}
 
-	return auth.PromptForNewWorkspace(cfg, currentDir, fetchInfo)
+func addNewWorkspace(cfg *config.Config, currentDir string) (*config.Workspace, error) {
+	return auth.PromptForNewWorkspace(cfg, currentDir, workspaceInfoFetcher(cfg))
 }
 
-func prepareLinearIssue(client *linear.Client, startMsg *messages.StartClaudeMsg) {
+// prepareLinearIssue moves the issue to In Progress and posts the start work
+// comment, queuing it in the workspace's journal if Linear can't be reached
+func prepareLinearIssue(ctx context.Context, client *linear.Client, workspaceID string, startMsg *messages.StartClaudeMsg) {
 	// Move issue to "In Progress" state
 	fmt.Print("Moving issue to In Progress...")
-	inProgressID, err := client.GetInProgressStateID(startMsg.Issue.Team.ID)
+	inProgressID, err := client.GetInProgressStateID(ctx, startMsg.Issue.Team.ID)
 	if err != nil {
 		fmt.Printf(" failed: %v\n", err)
 	} else if inProgressID != "" {
-		if err := client.UpdateIssueState(startMsg.Issue.ID, inProgressID); err != nil {
+		if _, err := client.UpdateIssueState(ctx, startMsg.Issue.ID, inProgressID); err != nil {
 			fmt.Printf(" failed: %v\n", err)
 		} else {
 			fmt.Println(" done")
@@ -190,72 +491,147 @@ func prepareLinearIssue(client *linear.Client, startMsg *messages.StartClaudeMsg
 		fmt.Println(" skipped (no In Progress state found)")
 	}
 
-	// Create comment if provided
 	if startMsg.Comment != "" {
 		fmt.Print("Adding comment to issue...")
-		_, err := client.CreateComment(startMsg.Issue.ID, startMsg.Comment)
-		if err != nil {
-			fmt.Printf(" failed: %v\n", err)
-		} else {
+		_, err := client.CreateComment(ctx, startMsg.Issue.ID, startMsg.Comment)
+		switch {
+		case err == nil:
 			fmt.Println(" done")
+		case linear.IsNetworkError(err):
+			if err := queueComment(workspaceID, startMsg); err != nil {
+				fmt.Printf(" failed: %v\n", err)
+			} else {
+				fmt.Println(" queued, Linear can't be reached")
+			}
+		default:
+			fmt.Printf(" failed: %v\n", err)
 		}
 	}
 
-	// Checkout branch if requested
-	if startMsg.UseBranch && startMsg.Issue.BranchName != "" {
-		checkoutBranch(startMsg.Issue.BranchName)
-	}
-
 	fmt.Println()
 }
 
-func checkoutBranch(branchName string) {
-	fmt.Printf("Checking out branch %s...", branchName)
+// queueComment queues the start work comment in the journal, to be posted
+// once linc is back online
+func queueComment(workspaceID string, startMsg *messages.StartClaudeMsg) error {
+	j, err := journal.Open(workspaceID)
+	if err != nil {
+		return err
+	}
+	return j.Append(journal.Mutation{
+		Kind:       journal.KindComment,
+		IssueID:    startMsg.Issue.ID,
+		Identifier: startMsg.Issue.Identifier,
+		Body:       startMsg.Comment,
+	})
+}
 
-	// Check if branch exists locally
-	checkCmd := exec.Command("git", "rev-parse", "--verify", branchName)
-	if err := checkCmd.Run(); err == nil {
-		// Branch exists, just checkout
-		cmd := exec.Command("git", "checkout", branchName)
-		if err := cmd.Run(); err != nil {
-			fmt.Printf(" failed: %v\n", err)
-			return
-		}
-		fmt.Println(" done")
+// resolveIssueBranch fetches the remote, so branches pushed from elsewhere
+// are found, and then works on Linear's suggested branch instead of the
+// templated one if only Linear's exists
+func resolveIssueBranch(startMsg *messages.StartClaudeMsg) {
+	if startMsg.Issue.BranchName == "" {
+		return
+	}
+	repo, err := git.Current()
+	if err != nil {
 		return
 	}
+	remote := repo.Remote()
+	fetchRemote(repo, remote)
+	startMsg.Issue.BranchName = branch.Prefer(startMsg.Issue.BranchName, startMsg.LinearBranch, func(name string) bool {
+		return repo.HasBranch(remote, name)
+	})
+}
 
-	// Check if branch exists on remote
-	checkRemoteCmd := exec.Command("git", "rev-parse", "--verify", "origin/"+branchName)
-	if err := checkRemoteCmd.Run(); err == nil {
+// checkoutBranch switches the current working tree to branchName. A branch
+// missing locally is created, tracking the remote's if there is one, else
+// starting from the base branch or, with fromHead, from HEAD. Changes
+// stashed when the branch was left are restored.
+func checkoutBranch(cfg *config.Config, branchName string, fromHead bool) error {
+	repo, err := git.Current()
+	if err != nil {
+		return err
+	}
+	remote := repo.Remote()
+
+	fmt.Printf("Checking out branch %s...", branchName)
+	var result string
+	switch {
+	case repo.LocalBranchExists(branchName):
+		// Branch exists, just checkout
+		err = repo.Checkout(branchName)
+		result = "done" + upstreamStatus(repo, branchName)
+	case remote != "" && repo.RemoteBranchExists(remote, branchName):
 		// Remote branch exists, checkout and track
-		cmd := exec.Command("git", "checkout", "-b", branchName, "--track", "origin/"+branchName)
-		if err := cmd.Run(); err != nil {
-			fmt.Printf(" failed: %v\n", err)
-			return
-		}
-		fmt.Println(" done (from remote)")
-		return
+		err = repo.TrackBranch(remote, branchName)
+		result = fmt.Sprintf("done (from %s)", remote)
+	default:
+		// Branch doesn't exist, create it
+		start := newBranchStart(cfg, repo, remote, fromHead)
+		err = repo.CreateBranch(branchName, start)
+		result = fmt.Sprintf("done (created from %s)", start)
+	}
+	if err != nil {
+		fmt.Println(" failed")
+		return err
 	}
+	fmt.Println(" " + result)
 
-	// Branch doesn't exist, create it
-	cmd := exec.Command("git", "checkout", "-b", branchName)
-	output, err := cmd.CombinedOutput()
+	restoreStash()
+	return nil
+}
+
+// newBranchStart returns where a new issue branch starts: the configured
+// base branch or the remote's default branch, or HEAD when stacking work on
+// it or no base branch can be found
+func newBranchStart(cfg *config.Config, repo *git.Repo, remote string, fromHead bool) string {
+	if fromHead {
+		return "HEAD"
+	}
+	repoRoot, _ := repo.MainRoot()
+	base, err := repo.BaseRef(remote, cfg.BaseBranchFor(repoRoot))
 	if err != nil {
-		fmt.Printf(" failed: %v (%s)\n", err, strings.TrimSpace(string(output)))
+		return "HEAD"
+	}
+	return base
+}
+
+// fetchRemote fetches remote so branches pushed from elsewhere are found.
+// When it can't be reached, linc carries on with what it knows.
+func fetchRemote(repo *git.Repo, remote string) {
+	if remote == "" {
 		return
 	}
-	fmt.Println(" done (created)")
+	fmt.Printf("Fetching %s...", remote)
+	if err := repo.Fetch(remote); err != nil {
+		fmt.Printf(" failed, continuing without: %v\n", err)
+		return
+	}
+	fmt.Println(" done")
 }
 
-func selectOrAddWorkspace(cfg *config.Config, currentDir string) (*config.Workspace, error) {
-	fetchInfo := func(apiKey string) (*auth.WorkspaceInfo, error) {
-		id, name, err := linear.FetchWorkspaceInfo(apiKey)
-		if err != nil {
-			return nil, err
-		}
-		return &auth.WorkspaceInfo{ID: id, Name: name}, nil
+// upstreamStatus describes how a branch compares to its upstream, e.g.
+// " (2 behind origin/eng-1)", or empty string when in sync or untracked
+func upstreamStatus(repo *git.Repo, branch string) string {
+	upstream := repo.Upstream(branch)
+	if upstream == "" {
+		return ""
+	}
+	ahead, behind, err := repo.AheadBehind(branch, upstream)
+	switch {
+	case err != nil || ahead+behind == 0:
+		return ""
+	case behind == 0:
+		return fmt.Sprintf(" (%d ahead of %s)", ahead, upstream)
+	case ahead == 0:
+		return fmt.Sprintf(" (%d behind %s)", behind, upstream)
 	}
+	return fmt.Sprintf(" (%d ahead, %d behind %s)", ahead, behind, upstream)
+}
+
+func selectOrAddWorkspace(cfg *config.Config, currentDir string) (*config.Workspace, error) {
+	fetchInfo := workspaceInfoFetcher(cfg)
 
 	if !cfg.HasWorkspaces() {
 		// No workspaces configured, must add one
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"linc/internal/config"
+	"linc/internal/git"
+	"linc/internal/journal"
+	"linc/internal/linear"
+	"linc/internal/linear/lineartest"
+	"linc/internal/provider"
+	"linc/internal/provider/echo"
+	"linc/internal/tui/messages"
+)
+
+// gitIn runs git in dir and returns its trimmed output
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// inNewRepo isolates linc and git from the user's home and runs the test in
+// a new repository on branch main with one commit
+func inNewRepo(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	gitIn(t, dir, "init", "--quiet", "--initial-branch", "main")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "add", "README")
+	gitIn(t, dir, "commit", "--quiet", "--message", "Initial commit")
+	t.Chdir(dir)
+	return dir
+}
+
+// startWork runs runStartWork with the echo provider against the fake server
+func startWork(t *testing.T, s *lineartest.Server, startMsg *messages.StartClaudeMsg) error {
+	t.Helper()
+	cfg := &config.Config{Provider: "echo"}
+	ws := &config.Workspace{ID: "ws-test", Name: "Acme", APIKey: lineartest.APIKey}
+	registry := provider.NewRegistry()
+	registry.Register("echo", echo.New())
+	_, err := runStartWork(s.Client(), cfg, ws, registry, startMsg)
+	return err
+}
+
+// issue returns an issue of the fake server's dataset as the TUI has it
+func issue(s *lineartest.Server, id string) linear.Issue {
+	var issue linear.Issue
+	s.Update(func(data *lineartest.Dataset) { issue = *data.Issue(id) })
+	return issue
+}
+
+func TestRunStartWork(t *testing.T) {
+	dir := inNewRepo(t)
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+
+	startMsg := &messages.StartClaudeMsg{
+		Issue:     issue(s, "issue-1"),
+		Comment:   "Starting with the CI config",
+		UseBranch: true,
+	}
+	if err := startWork(t, s, startMsg); err != nil {
+		t.Fatal(err)
+	}
+
+	after := issue(s, "issue-1")
+	if after.State.ID != "state-in-progress" {
+		t.Errorf("issue is in %s, want In Progress", after.State.Name)
+	}
+	if len(after.Comments) != 1 || after.Comments[0].Body != "Starting with the CI config" {
+		t.Errorf("comments = %+v, want the start work comment once", after.Comments)
+	}
+	if got := gitIn(t, dir, "branch", "--show-current"); got != startMsg.Issue.BranchName {
+		t.Errorf("on branch %s, want %s", got, startMsg.Issue.BranchName)
+	}
+}
+
+func TestRunStartWorkBranchSelection(t *testing.T) {
+	const templated = "feat/eng-1-set-up-ci-pipeline"
+
+	tests := []struct {
+		name     string
+		existing []string // branches created before starting
+		want     string
+	}{
+		{"neither exists", nil, templated},
+		{"only Linear's exists", []string{"eng-1-set-up-ci-pipeline"}, "eng-1-set-up-ci-pipeline"},
+		{"both exist", []string{"eng-1-set-up-ci-pipeline", templated}, templated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := inNewRepo(t)
+			for _, branch := range tt.existing {
+				gitIn(t, dir, "branch", branch)
+			}
+			s := lineartest.NewServer(nil)
+			defer s.Close()
+
+			linearIssue := issue(s, "issue-1")
+			templatedIssue := linearIssue
+			templatedIssue.BranchName = templated
+			startMsg := &messages.StartClaudeMsg{
+				Issue:        templatedIssue,
+				LinearBranch: linearIssue.BranchName,
+				UseBranch:    true,
+			}
+			if err := startWork(t, s, startMsg); err != nil {
+				t.Fatal(err)
+			}
+			if got := gitIn(t, dir, "branch", "--show-current"); got != tt.want {
+				t.Errorf("on branch %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunStartWorkFailureLeavesIssueUntouched(t *testing.T) {
+	dir := inNewRepo(t)
+	s := lineartest.NewServer(nil)
+	defer s.Close()
+	before := issue(s, "issue-1")
+
+	// The issue's branch changes README, which has local changes
+	gitIn(t, dir, "checkout", "--quiet", "-b", before.BranchName)
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("branch\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "commit", "--quiet", "--all", "--message", "Change README")
+	gitIn(t, dir, "checkout", "--quiet", "main")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("local\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := startWork(t, s, &messages.StartClaudeMsg{Issue: before, Comment: "Starting", UseBranch: true})
+	if err == nil {
+		t.Fatal("start work succeeded despite the failed checkout")
+	}
+
+	// Resuming isn't supported by echo, which is caught before git is touched
+	err = startWork(t, s, &messages.StartClaudeMsg{Issue: before, Comment: "Starting", Resume: true})
+	if err == nil {
+		t.Fatal("start work succeeded despite the provider check")
+	}
+
+	for _, op := range s.Operations() {
+		if op == "CreateComment" || op == "UpdateIssueState" {
+			t.Errorf("sent %s although the agent never started", op)
+		}
+	}
+	if after := issue(s, "issue-1"); after.State.ID != before.State.ID || len(after.Comments) != 0 {
+		t.Errorf("issue changed to %s with %d comments", after.State.Name, len(after.Comments))
+	}
+	if repo, err := git.Current(); err != nil || repo.Branch() != "main" {
+		t.Error("left main although the checkout failed")
+	}
+}
+
+func TestPrepareLinearIssueQueuesCommentOffline(t *testing.T) {
+	inNewRepo(t)
+	s := lineartest.NewServer(nil)
+	startMsg := &messages.StartClaudeMsg{Issue: issue(s, "issue-1"), Comment: "Starting offline"}
+	client := s.Client(linear.WithMaxRetries(0))
+	s.Close()
+
+	prepareLinearIssue(context.Background(), client, "ws-test", startMsg)
+
+	j, err := journal.Open("ws-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := j.Pending()
+	if len(pending) != 1 || pending[0].Kind != journal.KindComment || pending[0].Body != "Starting offline" {
+		t.Errorf("journal = %+v, want the comment queued", pending)
+	}
+	if !slices.ContainsFunc(pending, func(m journal.Mutation) bool { return m.Identifier == "ENG-1" }) {
+		t.Error("queued comment lacks the issue identifier")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"linc/internal/branch"
+	"linc/internal/config"
+	"linc/internal/git"
+	"linc/internal/linear"
+	"linc/internal/provider"
+)
+
+// renderPrompt renders the prompt template selected for the issue by config.
+// gitInfo describes where the agent will work; empty fields default to the
+// current repository and branch.
+func renderPrompt(cfg *config.Config, workspaceID, templateName string, gitInfo provider.GitInfo, issue linear.Issue, comment string, ctx *linear.IssueContext) (string, error) {
+	if templateName == "" {
+		labels := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			labels[i] = label.Name
+		}
+		templateName = cfg.PromptTemplateFor(workspaceID, issue.Team.Key, labels)
+	}
+
+	if gitInfo.Root == "" {
+		gitInfo.Root = git.GetRepoRoot()
+	}
+	if gitInfo.Branch == "" {
+		gitInfo.Branch = git.GetCurrentBranch()
+	}
+
+	tmpl, err := provider.LoadTemplate(templateName, provider.TemplateDirs(gitInfo.Root))
+	if err != nil {
+		return "", err
+	}
+	return provider.RenderPrompt(tmpl, provider.PromptData{
+		Issue:   issue,
+		Context: ctx,
+		Comment: comment,
+		Git:     gitInfo,
+	})
+}
+
+// runPromptCommand handles `linc prompt preview <ID>`, printing the prompt an
+// agent would be started with
+func runPromptCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "preview" {
+		return fmt.Errorf("usage: linc prompt preview [--template name] [--comment text] <issue ID>")
+	}
+
+	flags := flag.NewFlagSet("linc prompt preview", flag.ContinueOnError)
+	templateName := flags.String("template", "", "render this template instead of the configured one")
+	comment := flags.String("comment", "", "notes to render as the start work comment")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: linc prompt preview [--template name] [--comment text] <issue ID>")
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	ws := cfg.GetWorkspaceForDirectory(currentDir)
+	if ws == nil {
+		return fmt.Errorf("no Linear workspace is set up for %s, run linc first", currentDir)
+	}
+
+	ctx := context.Background()
+	client := newLinearClient(cfg, ws.APIKey)
+	issue, err := client.GetIssueWithContext(ctx, flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", flags.Arg(0), err)
+	}
+
+	var issueCtx *linear.IssueContext
+	if orgID, orgName, err := client.GetWorkspaceInfo(ctx); err == nil {
+		issueCtx = &linear.IssueContext{
+			OrganizationID:   orgID,
+			OrganizationName: orgName,
+		}
+	}
+
+	// Render for the issue's branch, as starting work uses it by default
+	var gitInfo provider.GitInfo
+	namer, _ := branch.New(cfg, ws.ID, git.GetMainRepoRoot())
+	linearBranch := issue.BranchName
+	issue.BranchName = namer.Name(*issue)
+	if repo, err := git.Current(); err == nil {
+		remote := repo.Remote()
+		issue.BranchName = branch.Prefer(issue.BranchName, linearBranch, func(name string) bool {
+			return repo.HasBranch(remote, name)
+		})
+	}
+	gitInfo.Branch = issue.BranchName
+
+	prompt, err := renderPrompt(cfg, ws.ID, *templateName, gitInfo, *issue, *comment, issueCtx)
+	if err != nil {
+		return err
+	}
+	fmt.Println(prompt)
+	return nil
+}
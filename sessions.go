@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"linc/internal/git"
+	"linc/internal/session"
+)
+
+// recordSession saves an agent session to the session journal. Failing to
+// record is only reported, as the journal is informational.
+func recordSession(sess *session.Session) {
+	store, err := session.Open()
+	if err == nil {
+		err = store.Save(sess)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not record session: %v\n", err)
+	}
+}
+
+// closeUnseenSessions fills in the end of the handed over sessions of the
+// given issues, or of all issues if none are given, whose agent has exited
+// since. A session's work ends at the head of its branch in its directory.
+func closeUnseenSessions(issueIDs ...string) {
+	if err := closeUnseen(issueIDs); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not update sessions: %v\n", err)
+	}
+}
+
+func closeUnseen(issueIDs []string) error {
+	store, err := session.Open()
+	if err != nil {
+		return err
+	}
+	if len(issueIDs) == 0 {
+		sessions, err := store.All()
+		if err != nil {
+			return err
+		}
+		for _, sess := range sessions {
+			if sess.EndedAt.IsZero() && !slices.Contains(issueIDs, sess.IssueID) {
+				issueIDs = append(issueIDs, sess.IssueID)
+			}
+		}
+	}
+	for _, issueID := range issueIDs {
+		if err := store.CloseUnseen(issueID, sessionHead); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sessionHead returns the commit a session's branch points to now, or HEAD
+// of its directory when it had no branch
+func sessionHead(sess session.Session) string {
+	if sess.Dir == "" {
+		return ""
+	}
+	repo, err := git.Open(sess.Dir)
+	if err != nil {
+		return ""
+	}
+	if sess.Branch != "" {
+		return repo.BranchHead(sess.Branch)
+	}
+	return repo.Head()
+}
+
+// agentSession returns the agent session to start an issue with: with resume,
+// the issue's previous session in dir, otherwise a new session
+func agentSession(issueID, providerID, dir string, resume bool) (id string, resuming bool) {
+	if resume {
+		store, err := session.Open()
+		if err == nil {
+			var sessions []session.Session
+			sessions, err = store.ForIssue(issueID)
+			if prev := session.Resumable(sessions, providerID, dir); prev != nil {
+				return prev.AgentSessionID, true
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read sessions: %v\n", err)
+		}
+		fmt.Println("No previous session to resume, starting a new one")
+	}
+
+	id, err := session.NewAgentID()
+	if err != nil {
+		return "", false
+	}
+	return id, false
+}
+
+// runSessionsCommand handles `linc sessions [issue]`, listing recorded agent
+// sessions, most recent first
+func runSessionsCommand(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: linc sessions [issue identifier]")
+	}
+
+	closeUnseenSessions()
+	store, err := session.Open()
+	if err != nil {
+		return err
+	}
+	sessions, err := store.All()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		var matching []session.Session
+		for _, sess := range sessions {
+			if strings.EqualFold(sess.Identifier, args[0]) || sess.IssueID == args[0] {
+				matching = append(matching, sess)
+			}
+		}
+		sessions = matching
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No agent sessions recorded")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tISSUE\tPROVIDER\tPLAN\tBRANCH\tDURATION\tSTATUS\tCOMMITS")
+	for _, sess := range sessions {
+		plan := ""
+		if sess.PlanMode {
+			plan = "yes"
+		}
+		duration := "-"
+		if d := sess.Duration(); d > 0 {
+			duration = d.Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			sess.StartedAt.Local().Format("2006-01-02 15:04"), sess.Identifier, sess.Provider, plan,
+			sess.Branch, duration, sess.Status(), sess.Commits())
+	}
+	return w.Flush()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"linc/internal/config"
+	"linc/internal/git"
+	"linc/internal/tui/messages"
+)
+
+// stashMessage names the stash of a branch's local changes made when
+// switching away from it to start work, so they can be found again
+func stashMessage(branch string) string {
+	return "linc: local changes on " + branch
+}
+
+// stashChanges stashes the current branch's local changes; they are restored
+// when linc next finds the branch checked out
+func stashChanges() error {
+	repo, err := git.Current()
+	if err != nil {
+		return err
+	}
+	branch := repo.Branch()
+	fmt.Printf("Stashing local changes on %s...", branch)
+	if err := repo.Stash(stashMessage(branch)); err != nil {
+		fmt.Println(" failed")
+		return err
+	}
+	fmt.Println(" done")
+	return nil
+}
+
+// restoreStash pops the changes stashed when switching away from the current
+// branch, if there are any
+func restoreStash() {
+	repo, err := git.Current()
+	if err != nil {
+		return
+	}
+	branch := repo.Branch()
+	if branch == "" {
+		return
+	}
+	ref := repo.FindStash(stashMessage(branch))
+	if ref == "" {
+		return
+	}
+	fmt.Printf("Restoring local changes stashed on %s...", branch)
+	if err := repo.PopStash(ref); err != nil {
+		fmt.Printf(" failed, they are kept in %s: %v\n", ref, err)
+		return
+	}
+	fmt.Println(" done")
+}
+
+// switchBranch checks out the issue's branch in the current working tree,
+// first stashing local changes if asked. A failed checkout restores them.
+func switchBranch(cfg *config.Config, startMsg *messages.StartClaudeMsg) error {
+	if startMsg.Stash {
+		if err := stashChanges(); err != nil {
+			return err
+		}
+	}
+	if err := checkoutBranch(cfg, startMsg.Issue.BranchName, startMsg.FromHead); err != nil {
+		if startMsg.Stash {
+			restoreStash()
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"linc/internal/config"
+	"linc/internal/git"
+)
+
+// worktreePath returns where the worktree for a branch of the current
+// repository lives
+func worktreePath(cfg *config.Config, branch string) (string, error) {
+	repoRoot := git.GetMainRepoRoot()
+	if repoRoot == "" {
+		return "", fmt.Errorf("worktrees need a git repository")
+	}
+	root, err := cfg.GetWorktreeRoot(repoRoot)
+	if err != nil {
+		return "", err
+	}
+	return git.WorktreeDir(root, branch), nil
+}
+
+// enterWorktree creates or reuses the worktree for a branch and makes it the
+// working directory, so the agent starts in it. A new branch starts like
+// one made by checkoutBranch. leave returns to the previous working
+// directory once the agent is done.
+func enterWorktree(cfg *config.Config, branch string, fromHead bool) (leave func(), err error) {
+	path, err := worktreePath(cfg, branch)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	repo, err := git.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	remote := repo.Remote()
+
+	fmt.Printf("Preparing worktree for %s...", branch)
+	reused, err := repo.AddWorktree(path, branch, newBranchStart(cfg, repo, remote, fromHead))
+	if err != nil {
+		fmt.Println(" failed")
+		return nil, err
+	}
+	if reused {
+		fmt.Printf(" done (reusing %s)\n", path)
+	} else {
+		fmt.Printf(" done (created %s)\n", path)
+	}
+
+	if err := os.Chdir(path); err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := os.Chdir(prev); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not return to %s: %v\n", prev, err)
+		}
+	}, nil
+}
+
+// runWorktreesCommand handles `linc worktrees [prune]`, listing or removing
+// the worktrees linc created for the current repository
+func runWorktreesCommand(cfg *config.Config, args []string) error {
+	if len(args) > 1 || (len(args) == 1 && args[0] != "prune") {
+		return fmt.Errorf("usage: linc worktrees [prune]")
+	}
+
+	repo, err := git.Current()
+	if err != nil {
+		return fmt.Errorf("not in a git repository")
+	}
+	repoRoot, err := repo.MainRoot()
+	if err != nil {
+		return err
+	}
+	root, err := cfg.GetWorktreeRoot(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := repo.Worktrees()
+	if err != nil {
+		return err
+	}
+	var managed []git.Worktree
+	for _, wt := range worktrees {
+		if config.IsSubdirectory(root, wt.Path) && wt.Path != root {
+			managed = append(managed, wt)
+		}
+	}
+
+	if len(args) == 0 {
+		if len(managed) == 0 {
+			fmt.Printf("No linc worktrees under %s\n", root)
+			return nil
+		}
+		for _, wt := range managed {
+			branch := wt.Branch
+			if branch == "" {
+				branch = "(detached)"
+			}
+			fmt.Printf("%-40s %s\n", branch, wt.Path)
+		}
+		return nil
+	}
+
+	// Drop records of worktrees deleted by hand first
+	if err := repo.PruneWorktrees(); err != nil {
+		return err
+	}
+	if len(managed) == 0 {
+		fmt.Printf("No linc worktrees under %s\n", root)
+		return nil
+	}
+
+	fmt.Printf("Remove %d worktree(s) under %s? Worktrees with uncommitted changes are kept. [y/N] ", len(managed), root)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+		return nil
+	}
+	for _, wt := range managed {
+		if _, err := os.Stat(wt.Path); os.IsNotExist(err) {
+			continue
+		}
+		fmt.Printf("Removing %s...", filepath.Base(wt.Path))
+		if err := repo.RemoveWorktree(wt.Path); err != nil {
+			fmt.Printf(" kept: %v\n", err)
+			continue
+		}
+		fmt.Println(" done")
+	}
+	return nil
+}